@@ -18,14 +18,62 @@ var (
 	)
 	organizations = field.StringSliceField(
 		"organizations",
-		field.WithDescription("Limit syncing to specific organizations by providing organization slugs."),
-		field.WithRequired(true),
+		field.WithDescription("Limit syncing to specific organizations by providing organization slugs. When empty, every organization the token's member belongs to is synced."),
+	)
+	excludeOrganizationsField = field.StringSliceField(
+		"exclude-organizations",
+		field.WithDescription("Organizations to skip when syncing, by organization ID or slug."),
+	)
+	boardsField = field.StringSliceField(
+		"boards",
+		field.WithDescription("Limit syncing to specific boards, by board ID or a regular expression matching the board name. When empty, every board is synced."),
+	)
+	excludeBoardsField = field.StringSliceField(
+		"exclude-boards",
+		field.WithDescription("Boards to skip when syncing, by board ID or a regular expression matching the board name."),
+	)
+	enterpriseIDField = field.StringField(
+		"enterprise-id",
+		field.WithDescription("The ID of the Trello Enterprise to sync admins and managed members from."),
+	)
+	emailMappingFileField = field.StringField(
+		"email-mapping-file",
+		field.WithDescription("The path to a CSV file of username,email rows, used for members whose email the token can't see."),
+	)
+	deleteBoardsField = field.BoolField(
+		"delete-boards",
+		field.WithDescription("Permanently delete boards instead of closing (archiving) them when a board is deleted."),
+	)
+	includeClosedBoardsField = field.BoolField(
+		"include-closed-boards",
+		field.WithDescription("Sync closed (archived) boards and their memberships. Their grants are labelled so they can be revoked together."),
+	)
+	personalBoardsField = field.BoolField(
+		"personal-boards",
+		field.WithDescription("Sync the boards synced members own outside any organization, under the member that owns them."),
+	)
+	changesFileField = field.StringField(
+		"changes-file",
+		field.WithDescription("The path to the change log written by webhook-serve. When it holds changes, only the boards, organizations and members it records are synced, and they're cleared once the sync completes."),
 	)
 
 	// ConfigurationFields defines the external configuration required for the
 	// connector to run. Note: these fields can be marked as optional or
 	// required.
-	ConfigurationFields = []field.SchemaField{apiKeyField, apiTokenField, organizations}
+	ConfigurationFields = []field.SchemaField{
+		apiKeyField,
+		apiTokenField,
+		organizations,
+		excludeOrganizationsField,
+		boardsField,
+		excludeBoardsField,
+		enterpriseIDField,
+		emailMappingFileField,
+		deleteBoardsField,
+		includeClosedBoardsField,
+		personalBoardsField,
+		changesFileField,
+	}
 
 	// FieldRelationships defines relationships between the fields listed in
 	// ConfigurationFields that can be automatically validated. For example, a
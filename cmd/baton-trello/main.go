@@ -22,7 +22,7 @@ var version = "dev"
 func main() {
 	ctx := context.Background()
 
-	_, cmd, err := config.DefineConfiguration(
+	v, cmd, err := config.DefineConfiguration(
 		ctx,
 		"baton-trello",
 		getConnector,
@@ -36,6 +36,7 @@ func main() {
 	}
 
 	cmd.Version = version
+	cmd.AddCommand(newWebhookServeCommand(ctx, v))
 
 	err = cmd.Execute()
 	if err != nil {
@@ -50,13 +51,31 @@ func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, e
 	apiKey := v.GetString(apiKeyField.FieldName)
 	apiToken := v.GetString(apiTokenField.FieldName)
 	orgs := v.GetStringSlice(organizations.FieldName)
+	deleteBoards := v.GetBool(deleteBoardsField.FieldName)
+	includeClosedBoards := v.GetBool(includeClosedBoardsField.FieldName)
+	personalBoards := v.GetBool(personalBoardsField.FieldName)
+	changesFile := v.GetString(changesFileField.FieldName)
 
 	trelloClient := client.NewClient(apiKey, apiToken, orgs)
+	trelloClient.ExcludedOrganizationIDs = v.GetStringSlice(excludeOrganizationsField.FieldName)
+	trelloClient.EnterpriseID = v.GetString(enterpriseIDField.FieldName)
+	boardFilter, err := client.NewBoardFilter(v.GetStringSlice(boardsField.FieldName), v.GetStringSlice(excludeBoardsField.FieldName))
+	if err != nil {
+		return nil, err
+	}
+	trelloClient.BoardFilter = boardFilter
+	if emailMappingFile := v.GetString(emailMappingFileField.FieldName); emailMappingFile != "" {
+		emails, err := client.LoadEmailMapping(emailMappingFile)
+		if err != nil {
+			return nil, err
+		}
+		trelloClient.EmailsByUsername = emails
+	}
 	if err := ValidateConfig(v); err != nil {
 		return nil, err
 	}
 
-	connectorBuilder, err := connectorSchema.New(ctx, trelloClient)
+	connectorBuilder, err := connectorSchema.New(ctx, trelloClient, deleteBoards, includeClosedBoards, personalBoards, changesFile)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
@@ -69,5 +88,5 @@ func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, e
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
 	}
-	return connector, nil
+	return connectorBuilder.WithChangeLogCleanup(connector), nil
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/logging"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/conductorone/baton-trello/pkg/webhook"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	callbackURLFlag   = "callback-url"
+	listenAddressFlag = "listen-address"
+	apiSecretFlag     = "api-secret"
+	logLevelFlag      = "log-level"
+)
+
+// newWebhookServeCommand returns the webhook-serve subcommand. It serves the webhook callback endpoint, registers
+// webhooks for the synced organizations and their boards, and records the boards and members that changed in the change
+// log the next sync is limited to.
+func newWebhookServeCommand(ctx context.Context, v *viper.Viper) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook-serve",
+		Short: "Receive Trello webhooks and record which boards and members changed",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := v.BindPFlags(cmd.Flags()); err != nil {
+				return err
+			}
+
+			return runWebhookServe(ctx, v)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.String(apiKeyField.FieldName, "", "required: The API key for your Trello account ($BATON_API_KEY)")
+	flags.String(apiTokenField.FieldName, "", "required: The API token for your Trello account ($BATON_API_TOKEN)")
+	flags.StringSlice(organizations.FieldName, nil, "Limit the webhooks to specific organizations ($BATON_ORGANIZATIONS)")
+	flags.StringSlice(excludeOrganizationsField.FieldName, nil, "Organizations to skip, by organization ID or slug ($BATON_EXCLUDE_ORGANIZATIONS)")
+	flags.StringSlice(boardsField.FieldName, nil, "Limit the webhooks to specific boards, by board ID or name pattern ($BATON_BOARDS)")
+	flags.StringSlice(excludeBoardsField.FieldName, nil, "Boards to skip, by board ID or name pattern ($BATON_EXCLUDE_BOARDS)")
+	flags.String(apiSecretFlag, "", "required: The secret of the API key, used to verify webhook signatures ($BATON_API_SECRET)")
+	flags.String(callbackURLFlag, "", "required: The public URL Trello posts webhook callbacks to ($BATON_CALLBACK_URL)")
+	flags.String(listenAddressFlag, ":8080", "The address the webhook server listens on ($BATON_LISTEN_ADDRESS)")
+	flags.String(changesFileField.FieldName, "trello-changes.json", "The path of the change log recording the boards and members that changed ($BATON_CHANGES_FILE)")
+	flags.String(logLevelFlag, "info", "The log level: debug, info, warn, error ($BATON_LOG_LEVEL)")
+
+	return cmd
+}
+
+func runWebhookServe(ctx context.Context, v *viper.Viper) error {
+	ctx, err := logging.Init(ctx, logging.WithLogFormat("console"), logging.WithLogLevel(v.GetString(logLevelFlag)))
+	if err != nil {
+		return err
+	}
+	l := ctxzap.Extract(ctx)
+
+	for _, required := range []string{apiKeyField.FieldName, apiTokenField.FieldName, apiSecretFlag, callbackURLFlag} {
+		if v.GetString(required) == "" {
+			return fmt.Errorf("trello-connector: %s is required", required)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	changes, err := webhook.OpenChangeLog(v.GetString(changesFileField.FieldName))
+	if err != nil {
+		return err
+	}
+
+	callbackURL := v.GetString(callbackURLFlag)
+	server := &http.Server{
+		Handler:           webhook.NewReceiver(v.GetString(apiSecretFlag), callbackURL, changes),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	// Note: the server must be listening before registering, since Trello checks the callback URL on creation.
+	listener, err := net.Listen("tcp", v.GetString(listenAddressFlag))
+	if err != nil {
+		return fmt.Errorf("trello-connector: failed to listen: %w", err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	trelloClient := client.NewClient(v.GetString(apiKeyField.FieldName), v.GetString(apiTokenField.FieldName), v.GetStringSlice(organizations.FieldName))
+	trelloClient.ExcludedOrganizationIDs = v.GetStringSlice(excludeOrganizationsField.FieldName)
+	trelloClient.BoardFilter, err = client.NewBoardFilter(v.GetStringSlice(boardsField.FieldName), v.GetStringSlice(excludeBoardsField.FieldName))
+	if err != nil {
+		return err
+	}
+	trelloClient, err = client.New(ctx, trelloClient)
+	if err != nil {
+		return err
+	}
+
+	created, err := webhook.Register(ctx, trelloClient, callbackURL)
+	if err != nil {
+		_ = server.Close()
+		return err
+	}
+	l.Info("trello-connector: serving webhooks", zap.String("address", listener.Addr().String()), zap.Int("registered", created))
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return server.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BoardFilter decides which boards are synced. Each pattern matches a board whose ID is the pattern, or whose name
+// matches the pattern as a regular expression.
+type BoardFilter struct {
+	include []boardPattern
+	exclude []boardPattern
+}
+
+type boardPattern struct {
+	value string
+	name  *regexp.Regexp
+}
+
+func (p boardPattern) match(board *Board) bool {
+	return board.ID == p.value || p.name.MatchString(board.Name)
+}
+
+// NewBoardFilter returns a filter keeping the boards matching any of the include patterns, or every board when there
+// are none, except the boards matching any of the exclude patterns.
+func NewBoardFilter(include, exclude []string) (*BoardFilter, error) {
+	includePatterns, err := compileBoardPatterns(include)
+	if err != nil {
+		return nil, err
+	}
+
+	excludePatterns, err := compileBoardPatterns(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoardFilter{
+		include: includePatterns,
+		exclude: excludePatterns,
+	}, nil
+}
+
+func compileBoardPatterns(values []string) ([]boardPattern, error) {
+	var patterns []boardPattern
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		name, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("trello-connector: invalid board pattern %q: %w", value, err)
+		}
+		patterns = append(patterns, boardPattern{value: value, name: name})
+	}
+
+	return patterns, nil
+}
+
+// Match reports whether the board is synced. A nil filter keeps every board.
+func (f *BoardFilter) Match(board *Board) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, pattern := range f.exclude {
+		if pattern.match(board) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	for _, pattern := range f.include {
+		if pattern.match(board) {
+			return true
+		}
+	}
+
+	return false
+}
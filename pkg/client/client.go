@@ -5,23 +5,45 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/conductorone/baton-sdk/pkg/annotations"
-	"github.com/conductorone/baton-sdk/pkg/ratelimit"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 const (
 	domain = "https://api.trello.com/1"
 
+	// enterpriseMembersPageSize is the most enterprise members Trello returns per request.
+	enterpriseMembersPageSize = 100
+
+	createBoard                  = "/boards"
 	getBoardById                 = "/boards/%s"
+	getActionsByBoard            = "/boards/%s/actions"
 	getBoardsByOrganization      = "/organizations/%s/boards"
+	getBoardsByMember            = "/members/%s/boards"
+	getEnterpriseAdmins          = "/enterprises/%s/admins"
+	getEnterpriseById            = "/enterprises/%s"
+	getEnterpriseMembers         = "/enterprises/%s/members"
 	getMemberById                = "/members/%s"
 	getMembershipsByBoard        = "/boards/%s/memberships"
 	getMembershipsByOrganization = "/organizations/%s/memberships"
 	getOrganizationById          = "/organizations/%s"
+	getActionsByOrganization     = "/organizations/%s/actions"
+	getOrganizationsByMember     = "/members/%s/organizations"
+	getTokenById                 = "/tokens/%s"
+	getTokensByMember            = "/members/%s/tokens"
+	getWebhooksByToken           = "/tokens/%s/webhooks"
+	createWebhook                = "/webhooks"
 	getUsersByOrganization       = "/organizations/%s/members"
+	updateBoardMember            = "/boards/%s/members/%s"
+	updateOrganizationMember     = "/organizations/%s/members/%s"
+	inviteOrganizationMember     = "/organizations/%s/members"
+	deactivateOrganizationMember = "/organizations/%s/members/%s/deactivated"
 )
 
 type TrelloClient struct {
@@ -29,7 +51,23 @@ type TrelloClient struct {
 	ApiKey          string
 	BaseDomain      string
 	OrganizationIDs []string
-	wrapper         *uhttp.BaseHttpClient
+	// ExcludedOrganizationIDs lists the organizations, by ID or name, that are never synced.
+	ExcludedOrganizationIDs []string
+	EnterpriseID            string
+	// EmailsByUsername maps usernames to emails for members whose email the token can't see.
+	EmailsByUsername map[string]string
+	// BoardFilter limits the boards listed for an organization. When nil, every board is listed.
+	BoardFilter        *BoardFilter
+	wrapper            *uhttp.BaseHttpClient
+	members            map[string]User
+	membersMutex       sync.RWMutex
+	organizations      []string
+	organizationsMutex sync.Mutex
+	emails             map[string]string
+	emailsMutex        sync.Mutex
+	limiter            rateLimiter
+	// quietHttpClient doesn't log requests, for the endpoints carrying the API token in their path.
+	quietHttpClient *http.Client
 }
 
 func New(ctx context.Context, trelloClient *TrelloClient) (*TrelloClient, error) {
@@ -38,6 +76,10 @@ func New(ctx context.Context, trelloClient *TrelloClient) (*TrelloClient, error)
 		clientToken     = trelloClient.ApiToken
 		clientDomain    = trelloClient.BaseDomain
 		organizationIDs = trelloClient.OrganizationIDs
+		excludedOrgIDs  = trelloClient.ExcludedOrganizationIDs
+		enterpriseID    = trelloClient.EnterpriseID
+		emailMapping    = trelloClient.EmailsByUsername
+		boardFilter     = trelloClient.BoardFilter
 	)
 
 	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
@@ -50,12 +92,22 @@ func New(ctx context.Context, trelloClient *TrelloClient) (*TrelloClient, error)
 		return nil, err
 	}
 
+	quietHttpClient, err := uhttp.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	client := TrelloClient{
-		wrapper:         cli,
-		ApiKey:          clientKey,
-		ApiToken:        clientToken,
-		BaseDomain:      clientDomain,
-		OrganizationIDs: organizationIDs,
+		wrapper:                 cli,
+		ApiKey:                  clientKey,
+		ApiToken:                clientToken,
+		BaseDomain:              clientDomain,
+		OrganizationIDs:         organizationIDs,
+		ExcludedOrganizationIDs: excludedOrgIDs,
+		EnterpriseID:            enterpriseID,
+		EmailsByUsername:        emailMapping,
+		BoardFilter:             boardFilter,
+		quietHttpClient:         quietHttpClient,
 	}
 
 	return &client, nil
@@ -75,25 +127,47 @@ func NewClient(apiKey, apiToken string, organizationIDs []string, httpClient ...
 	}
 }
 
-func (c *TrelloClient) ListUsers(ctx context.Context) ([]User, annotations.Annotations, error) {
+// ListUsers returns the members of an organization.
+// Note: Trello API doesn't support pagination for member queries, so all the members of the organization are returned.
+func (c *TrelloClient) ListUsers(ctx context.Context, organizationID string) ([]User, annotations.Annotations, error) {
 	l := ctxzap.Extract(ctx)
 	var res []User
-	var annotation annotations.Annotations
 
-	for _, id := range c.OrganizationIDs {
-		queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getUsersByOrganization, id))
-		if err != nil {
-			l.Error(fmt.Sprintf("Error creating url: %s", err))
-			return nil, nil, err
-		}
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getUsersByOrganization, organizationID))
+	if err != nil {
+		l.Error(fmt.Sprintf("Error creating url: %s", err))
+		return nil, nil, err
+	}
 
-		annotation, err = c.getResourcesFromAPI(ctx, queryUrl, &res)
-		if err != nil {
-			l.Error(fmt.Sprintf("Error getting resources: %s", err))
-			return nil, nil, err
+	// Note: the fields are the ones user resources are built from; members only carry the fields asked for.
+	queryUrl, err = withQueryParams(queryUrl, url.Values{"fields": {"fullName,username,avatarUrl,email,dateLastActive"}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	annotation, err := c.getResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		l.Error(fmt.Sprintf("Error getting resources: %s", err))
+		return nil, nil, err
+	}
+
+	// Note: the members endpoint doesn't report whether a member is deactivated or unconfirmed, only the memberships do.
+	// A member is listed under every organization it belongs to, so the status covers all of them.
+	memberships, err := c.listMemberStatuses(ctx)
+	if err != nil {
+		l.Error(fmt.Sprintf("Error getting memberships: %s", err))
+		return nil, nil, err
+	}
+
+	for i := range res {
+		if membership, ok := memberships[res[i].ID]; ok {
+			res[i].Deactivated = membership.Deactivated
+			res[i].Unconfirmed = membership.Unconfirmed
 		}
 	}
 
+	c.setEmails(ctx, res)
+
 	return res, annotation, nil
 }
 
@@ -101,7 +175,12 @@ func (c *TrelloClient) ListOrganizations(ctx context.Context) ([]Organization, a
 	var res []Organization
 	annotation := annotations.Annotations{}
 
-	for _, id := range c.OrganizationIDs {
+	organizationIDs, err := c.ListOrganizationIDs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, id := range organizationIDs {
 		organizationDetail, incomingAnnotation, err := c.GetOrganizationDetail(ctx, id)
 		if err != nil {
 			return nil, nil, err
@@ -118,29 +197,233 @@ func (c *TrelloClient) ListOrganizations(ctx context.Context) ([]Organization, a
 	return res, annotation, nil
 }
 
-func (c *TrelloClient) ListBoards(ctx context.Context) ([]Board, annotations.Annotations, error) {
+// ListOrganizationIDs returns the organizations to sync. The configured organizations act as an allowlist; when
+// none are configured, every organization the token's member belongs to is discovered. Excluded organizations are
+// left out either way. The result is resolved once and reused for the rest of the sync.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-organizations-get
+func (c *TrelloClient) ListOrganizationIDs(ctx context.Context) ([]string, error) {
+	c.organizationsMutex.Lock()
+	defer c.organizationsMutex.Unlock()
+
+	if c.organizations != nil {
+		return c.organizations, nil
+	}
+
+	var organizations []Organization
+	if len(c.OrganizationIDs) > 0 {
+		for _, id := range c.OrganizationIDs {
+			organizations = append(organizations, Organization{ID: id})
+		}
+	} else {
+		queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getOrganizationsByMember, "me"))
+		if err != nil {
+			return nil, err
+		}
+
+		queryUrl, err = withQueryParams(queryUrl, url.Values{"fields": {"id,name"}})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = c.getResourcesFromAPI(ctx, queryUrl, &organizations)
+		if err != nil {
+			return nil, fmt.Errorf("trello-connector: failed to discover organizations: %w", err)
+		}
+	}
+
+	excluded := make(map[string]bool, len(c.ExcludedOrganizationIDs))
+	for _, id := range c.ExcludedOrganizationIDs {
+		excluded[id] = true
+	}
+
+	organizationIDs := make([]string, 0, len(organizations))
+	for _, organization := range organizations {
+		if excluded[organization.ID] || (organization.Name != "" && excluded[organization.Name]) {
+			continue
+		}
+		organizationIDs = append(organizationIDs, organization.ID)
+	}
+
+	c.organizations = organizationIDs
+
+	return organizationIDs, nil
+}
+
+// ListBoards returns the boards of an organization that match the board filter.
+// Note: Trello API doesn't support pagination for boards by organization queries.
+func (c *TrelloClient) ListBoards(ctx context.Context, organizationID string) ([]Board, annotations.Annotations, error) {
 	l := ctxzap.Extract(ctx)
-	var resources []Board
-	var annotation annotations.Annotations
+	var res []Board
+
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getBoardsByOrganization, organizationID))
+	if err != nil {
+		l.Error(fmt.Sprintf("Error creating url: %s", err))
+		return nil, nil, err
+	}
+
+	annotation, err := c.getResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		l.Error(fmt.Sprintf("Error getting resources: %s", err))
+		return nil, nil, err
+	}
+
+	// Note: boards are filtered here, so filtered out boards never cost membership or detail requests.
+	boards := res[:0]
+	for _, board := range res {
+		if c.BoardFilter.Match(&board) {
+			boards = append(boards, board)
+		}
+	}
+
+	return boards, annotation, nil
+}
+
+// ListPersonalBoards returns the boards of a member that are outside any organization and match the board filter.
+// Only the boards the token's member can see are returned.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-boards-get
+func (c *TrelloClient) ListPersonalBoards(ctx context.Context, memberID string) ([]Board, annotations.Annotations, error) {
+	var res []Board
+
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getBoardsByMember, memberID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	annotation, err := c.getResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trello-connector: failed to list boards of member %s: %w", memberID, err)
+	}
+
+	boards := res[:0]
+	for _, board := range res {
+		if board.IdOrganization == "" && c.BoardFilter.Match(&board) {
+			boards = append(boards, board)
+		}
+	}
+
+	return boards, annotation, nil
+}
+
+// ListBoardActions returns the actions of a board matching the query, newest first.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-boardid-actions-get
+func (c *TrelloClient) ListBoardActions(ctx context.Context, boardID string, query ActionsQuery) ([]Action, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getActionsByBoard, boardID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.listActions(ctx, queryUrl, query)
+}
+
+// ListOrganizationActions returns the actions of an organization matching the query, newest first.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-actions-get
+func (c *TrelloClient) ListOrganizationActions(ctx context.Context, organizationID string, query ActionsQuery) ([]Action, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getActionsByOrganization, organizationID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.listActions(ctx, queryUrl, query)
+}
+
+func (c *TrelloClient) listActions(ctx context.Context, queryUrl string, query ActionsQuery) ([]Action, annotations.Annotations, error) {
+	params := url.Values{}
+	if len(query.Filter) > 0 {
+		params.Set("filter", strings.Join(query.Filter, ","))
+	}
+	if query.Since != "" {
+		params.Set("since", query.Since)
+	}
+	if query.Before != "" {
+		params.Set("before", query.Before)
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	queryUrl, err := withQueryParams(queryUrl, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Note: actions are read past the response cache, since polls repeat the same query until new actions appear.
+	var res []Action
+	annotation, err := c.getUncachedResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
+}
+
+// GetEnterprise returns the details of an enterprise.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-enterprises/#api-enterprises-id-get
+func (c *TrelloClient) GetEnterprise(ctx context.Context, enterpriseID string) (*Enterprise, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getEnterpriseById, enterpriseID))
+	if err != nil {
+		return nil, nil, err
+	}
+	var res *Enterprise
+	_, annotation, err := c.doRequest(ctx, http.MethodGet, queryUrl, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListEnterpriseAdmins returns the admins of an enterprise.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-enterprises/#api-enterprises-id-admins-get
+func (c *TrelloClient) ListEnterpriseAdmins(ctx context.Context, enterpriseID string) ([]User, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getEnterpriseAdmins, enterpriseID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res []User
+	annotation, err := c.getResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for _, id := range c.OrganizationIDs {
-		var res []Board
-		queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getBoardsByOrganization, id))
+	return res, annotation, nil
+}
+
+// ListEnterpriseMembers returns the licensed members managed by an enterprise, along with their emails. Members are
+// returned a page at a time, so pages are read until one comes back short.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-enterprises/#api-enterprises-id-members-get
+func (c *TrelloClient) ListEnterpriseMembers(ctx context.Context, enterpriseID string) ([]User, annotations.Annotations, error) {
+	baseUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getEnterpriseMembers, enterpriseID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		members    []User
+		annotation annotations.Annotations
+	)
+	for {
+		// Note: the email of managed members is only returned when requested.
+		queryUrl, err := withQueryParams(baseUrl, url.Values{
+			"fields":     {"fullName,username,email"},
+			"count":      {strconv.Itoa(enterpriseMembersPageSize)},
+			"startIndex": {strconv.Itoa(len(members))},
+		})
 		if err != nil {
-			l.Error(fmt.Sprintf("Error creating url: %s", err))
 			return nil, nil, err
 		}
 
+		var res []User
 		annotation, err = c.getResourcesFromAPI(ctx, queryUrl, &res)
-		if err == nil {
-			resources = append(resources, res...)
-		} else {
-			l.Error(fmt.Sprintf("Error getting resources: %s", err))
+		if err != nil {
 			return nil, nil, err
 		}
-	}
+		members = append(members, res...)
 
-	return resources, annotation, nil
+		if len(res) < enterpriseMembersPageSize {
+			return members, annotation, nil
+		}
+	}
 }
 
 func (c *TrelloClient) GetBoardDetails(ctx context.Context, boardID string) (*Board, annotations.Annotations, error) {
@@ -157,6 +440,62 @@ func (c *TrelloClient) GetBoardDetails(ctx context.Context, boardID string) (*Bo
 	return res, annotation, nil
 }
 
+// CreateBoard creates a board inside an organization.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-post
+func (c *TrelloClient) CreateBoard(ctx context.Context, organizationID, name, description, permissionLevel string) (*Board, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, createBoard)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := createBoardBody{
+		Name:            name,
+		Description:     description,
+		IdOrganization:  organizationID,
+		PermissionLevel: permissionLevel,
+	}
+
+	var res *Board
+	_, annotation, err := c.doRequest(ctx, http.MethodPost, queryUrl, &res, uhttp.WithJSONBody(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
+}
+
+// CloseBoard closes (archives) a board. Closed boards can be reopened by a board admin.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-put
+func (c *TrelloClient) CloseBoard(ctx context.Context, boardID string) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getBoardById, boardID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodPut, queryUrl, nil, uhttp.WithJSONBody(closeBoardBody{Closed: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// DeleteBoard permanently deletes a board.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-delete
+func (c *TrelloClient) DeleteBoard(ctx context.Context, boardID string) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getBoardById, boardID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodDelete, queryUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
 func (c *TrelloClient) ListMembershipsByBoard(ctx context.Context, boardID string) ([]User, error) {
 	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getMembershipsByBoard, boardID))
 	if err != nil {
@@ -166,6 +505,38 @@ func (c *TrelloClient) ListMembershipsByBoard(ctx context.Context, boardID strin
 	return c.listMembershipsByResource(ctx, queryUrl)
 }
 
+// AddMemberToBoard adds a member to a board, or updates the member type when the member already belongs to it.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-members-idmember-put
+func (c *TrelloClient) AddMemberToBoard(ctx context.Context, boardID, memberID, memberType string) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(updateBoardMember, boardID, memberID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodPut, queryUrl, nil, uhttp.WithJSONBody(memberTypeBody{Type: memberType}))
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// RemoveMemberFromBoard removes a member from a board.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-members-idmember-delete
+func (c *TrelloClient) RemoveMemberFromBoard(ctx context.Context, boardID, memberID string) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(updateBoardMember, boardID, memberID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodDelete, queryUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
 func (c *TrelloClient) GetOrganizationDetail(ctx context.Context, organizationID string) (*Organization, annotations.Annotations, error) {
 	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getOrganizationById, organizationID))
 	if err != nil {
@@ -189,28 +560,383 @@ func (c *TrelloClient) ListMembershipsByOrg(ctx context.Context, resourceID stri
 	return c.listMembershipsByResource(ctx, queryUrl)
 }
 
-func (c *TrelloClient) listMembershipsByResource(ctx context.Context, queryUrl string) ([]User, error) {
+// GetOrganizationMembership returns the membership of a member in an organization, or nil when the member
+// doesn't belong to it.
+func (c *TrelloClient) GetOrganizationMembership(ctx context.Context, organizationID, memberID string) (*User, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getMembershipsByOrganization, organizationID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.findMembership(ctx, queryUrl, memberID)
+}
+
+// GetBoardMembership returns the membership of a member in a board, or nil when the member doesn't belong to it.
+func (c *TrelloClient) GetBoardMembership(ctx context.Context, boardID, memberID string) (*User, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getMembershipsByBoard, boardID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.findMembership(ctx, queryUrl, memberID)
+}
+
+func (c *TrelloClient) findMembership(ctx context.Context, queryUrl, memberID string) (*User, annotations.Annotations, error) {
+	// Note: memberships are read past the response cache, since they're checked right after being changed.
 	var res []User
-	var resources []User
+	annotation, err := c.getUncachedResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, membership := range res {
+		if membership.MemberID == memberID {
+			membershipCopy := membership
+			return &membershipCopy, annotation, nil
+		}
+	}
+
+	return nil, annotation, nil
+}
 
-	_, err := c.getResourcesFromAPI(ctx, queryUrl, &res)
+// listUncachedMemberships returns the memberships of an organization, read past the response cache.
+func (c *TrelloClient) listUncachedMemberships(ctx context.Context, organizationID string) ([]User, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getMembershipsByOrganization, organizationID))
 	if err != nil {
 		return nil, err
 	}
 
-	for _, resource := range res {
-		memberDetail, _, err := c.GetMemberDetails(ctx, resource.MemberID)
+	var res []User
+	if _, err := c.getUncachedResourcesFromAPI(ctx, queryUrl, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddMemberToOrganization adds a member to an organization, or updates the member type when the member already
+// belongs to it.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-idmember-put
+func (c *TrelloClient) AddMemberToOrganization(ctx context.Context, organizationID, memberID, memberType string) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(updateOrganizationMember, organizationID, memberID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodPut, queryUrl, nil, uhttp.WithJSONBody(memberTypeBody{Type: memberType}))
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// InviteMemberToOrganization invites a person to an organization by email and returns the invited member.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-put
+func (c *TrelloClient) InviteMemberToOrganization(ctx context.Context, organizationID, email, fullName string) (*User, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(inviteOrganizationMember, organizationID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Note: the response lists every member of the organization, so the invited member is told apart by comparing
+	// the memberships held before the invitation with the ones held after.
+	previousMemberships, err := c.listUncachedMemberships(ctx, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	previousMemberIDs := make(map[string]bool, len(previousMemberships))
+	for _, membership := range previousMemberships {
+		previousMemberIDs[membership.MemberID] = true
+	}
+
+	body := inviteMemberBody{
+		Email:    email,
+		FullName: fullName,
+		Type:     "normal",
+	}
+
+	var res *OrganizationMembers
+	_, annotation, err := c.doRequest(ctx, http.MethodPut, queryUrl, &res, uhttp.WithJSONBody(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res == nil {
+		return nil, annotation, fmt.Errorf("empty response inviting %s to organization %s", email, organizationID)
+	}
+
+	member := res.invitedMember(previousMemberIDs)
+	if member == nil {
+		return nil, annotation, fmt.Errorf("invited member %s not found in organization %s", email, organizationID)
+	}
+	if member.Email == "" {
+		member.Email = email
+	}
+
+	return member, annotation, nil
+}
+
+// RemoveMemberFromOrganization removes a member from an organization.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-idmember-delete
+func (c *TrelloClient) RemoveMemberFromOrganization(ctx context.Context, organizationID, memberID string) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(updateOrganizationMember, organizationID, memberID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodDelete, queryUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// DeactivateOrganizationMember deactivates a member of an organization. Deactivated members keep their history
+// but lose access to the organization and its boards.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-idmember-deactivated-put
+func (c *TrelloClient) DeactivateOrganizationMember(ctx context.Context, organizationID, memberID string) (annotations.Annotations, error) {
+	return c.setOrganizationMemberDeactivated(ctx, organizationID, memberID, true)
+}
+
+// ReactivateOrganizationMember reactivates a deactivated member of an organization.
+func (c *TrelloClient) ReactivateOrganizationMember(ctx context.Context, organizationID, memberID string) (annotations.Annotations, error) {
+	return c.setOrganizationMemberDeactivated(ctx, organizationID, memberID, false)
+}
+
+func (c *TrelloClient) setOrganizationMemberDeactivated(ctx context.Context, organizationID, memberID string, deactivated bool) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(deactivateOrganizationMember, organizationID, memberID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodPut, queryUrl, nil, uhttp.WithJSONBody(deactivatedBody{Value: deactivated}))
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// listMemberStatuses returns the status of every member of the synced organizations keyed by member ID. A member
+// active in any organization is active; otherwise it keeps the flags of its memberships, so it's only reported
+// deactivated or unconfirmed when it is in every organization it belongs to.
+func (c *TrelloClient) listMemberStatuses(ctx context.Context) (map[string]User, error) {
+	organizationIDs, err := c.ListOrganizationIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]User)
+	active := make(map[string]bool)
+	for _, organizationID := range organizationIDs {
+		memberships, err := c.listMembershipStatuses(ctx, organizationID)
 		if err != nil {
 			return nil, err
 		}
-		memberDetail.MemberType = resource.MemberType
 
-		resources = append(resources, *memberDetail)
+		for memberID, membership := range memberships {
+			if !membership.Deactivated && !membership.Unconfirmed {
+				active[memberID] = true
+			}
+
+			status := statuses[memberID]
+			status.Deactivated = status.Deactivated || membership.Deactivated
+			status.Unconfirmed = status.Unconfirmed || membership.Unconfirmed
+			statuses[memberID] = status
+		}
+	}
+
+	for memberID := range active {
+		statuses[memberID] = User{}
+	}
+
+	return statuses, nil
+}
+
+// listMembershipStatuses returns the memberships of an organization keyed by member ID.
+func (c *TrelloClient) listMembershipStatuses(ctx context.Context, organizationID string) (map[string]User, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getMembershipsByOrganization, organizationID))
+	if err != nil {
+		return nil, err
+	}
+
+	var res []User
+	_, err = c.getResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := make(map[string]User, len(res))
+	for _, membership := range res {
+		memberships[membership.MemberID] = membership
+	}
+
+	return memberships, nil
+}
+
+// listMembershipsByResource returns the members of a board or organization. Member details are requested inline
+// with the memberships, and only members missing from the response are fetched one by one.
+func (c *TrelloClient) listMembershipsByResource(ctx context.Context, queryUrl string) ([]User, error) {
+	var res []Membership
+	var resources []User
+
+	queryUrl, err := withQueryParams(queryUrl, url.Values{"member": {"true"}})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.getResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range res {
+		var memberDetail *User
+		if resource.Member != nil && resource.Member.ID != "" {
+			memberDetail = resource.Member
+			c.cacheMember(*memberDetail)
+		} else {
+			memberDetail, err = c.getCachedMemberDetails(ctx, resource.MemberID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		member := *memberDetail
+		member.MemberID = resource.MemberID
+		member.MemberType = resource.MemberType
+		member.Deactivated = resource.Deactivated
+		member.Unconfirmed = resource.Unconfirmed
+
+		resources = append(resources, member)
 	}
 
 	return resources, nil
 }
 
+// getCachedMemberDetails returns the details of a member, fetching them only the first time the member is seen.
+func (c *TrelloClient) getCachedMemberDetails(ctx context.Context, memberID string) (*User, error) {
+	c.membersMutex.RLock()
+	member, ok := c.members[memberID]
+	c.membersMutex.RUnlock()
+	if ok {
+		return &member, nil
+	}
+
+	memberDetail, _, err := c.GetMemberDetails(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	c.cacheMember(*memberDetail)
+
+	return memberDetail, nil
+}
+
+func (c *TrelloClient) cacheMember(member User) {
+	c.membersMutex.Lock()
+	defer c.membersMutex.Unlock()
+
+	if c.members == nil {
+		c.members = make(map[string]User)
+	}
+	c.members[member.ID] = member
+}
+
+// GetToken returns the metadata of an API token, including its expiry and the permissions it grants. The token is
+// part of the path, so the request is kept out of the request logs and the response cache.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-get
+func (c *TrelloClient) GetToken(ctx context.Context, token string) (*Token, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getTokenById, token))
+	if err != nil {
+		return nil, nil, err
+	}
+	var res *Token
+	_, annotation, err := c.doRequestWith(ctx, c.sendQuietly, http.MethodGet, queryUrl, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListMemberTokens returns the API tokens a member has authorized for applications.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-tokens-get
+func (c *TrelloClient) ListMemberTokens(ctx context.Context, memberID string) ([]Token, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getTokensByMember, memberID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res []Token
+	annotation, err := c.getResourcesFromAPI(ctx, queryUrl, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
+}
+
+// DeleteToken deletes an API token, revoking the access it granted to its application. The token routes take either
+// the token or its ID, and the tokens listed for a member only carry their ID, so tokens are deleted by ID.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-delete
+func (c *TrelloClient) DeleteToken(ctx context.Context, tokenID string) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getTokenById, tokenID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, annotation, err := c.doRequest(ctx, http.MethodDelete, queryUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// ListWebhooks returns the webhooks registered with the client's token. The token is part of the path, so the
+// request is kept out of the request logs and the response cache.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-webhooks-get
+func (c *TrelloClient) ListWebhooks(ctx context.Context) ([]Webhook, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getWebhooksByToken, c.ApiToken))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res []Webhook
+	_, annotation, err := c.doRequestWith(ctx, c.sendQuietly, http.MethodGet, queryUrl, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
+}
+
+// CreateWebhook registers a webhook that posts the actions of a model to the callback URL. Trello checks that the
+// callback URL answers a HEAD request before creating the webhook.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-webhooks/#api-webhooks-post
+func (c *TrelloClient) CreateWebhook(ctx context.Context, modelID, callbackURL, description string) (*Webhook, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(c.BaseDomain, createWebhook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := createWebhookBody{
+		IdModel:     modelID,
+		CallbackURL: callbackURL,
+		Description: description,
+	}
+
+	var res *Webhook
+	_, annotation, err := c.doRequest(ctx, http.MethodPost, queryUrl, &res, uhttp.WithJSONBody(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
+}
+
 func (c *TrelloClient) GetMemberDetails(ctx context.Context, memberID string) (*User, annotations.Annotations, error) {
 	queryUrl, err := url.JoinPath(c.BaseDomain, fmt.Sprintf(getMemberById, memberID))
 	if err != nil {
@@ -244,30 +970,100 @@ func (c *TrelloClient) doRequest(
 	method string,
 	endpointUrl string,
 	res interface{},
+	reqOptions ...uhttp.RequestOption,
 ) (http.Header, annotations.Annotations, error) {
-	var (
-		resp *http.Response
-		err  error
-	)
+	return c.doRequestWith(ctx, c.send, method, endpointUrl, res, reqOptions...)
+}
 
-	urlAddress, err := url.Parse(authorizeEndpointUrl(c, endpointUrl))
+// sender makes a single request, decoding the response into res.
+type sender func(ctx context.Context, method string, urlAddress *url.URL, res interface{}, reqOptions []uhttp.RequestOption) (*http.Response, error)
 
+// doRequestWith makes a request through the sender, retrying it when Trello answers with 429.
+func (c *TrelloClient) doRequestWith(
+	ctx context.Context,
+	send sender,
+	method string,
+	endpointUrl string,
+	res interface{},
+	reqOptions ...uhttp.RequestOption,
+) (http.Header, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	urlAddress, err := url.Parse(endpointUrl)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	reqOptions = append(
+		reqOptions,
+		uhttp.WithContentTypeJSONHeader(),
+		uhttp.WithAcceptJSONHeader(),
+		uhttp.WithHeader("Authorization", c.authorizationHeader()),
+	)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		resp, err := send(ctx, method, urlAddress, res, reqOptions)
+		if resp != nil {
+			c.limiter.update(resp.Header)
+		}
+
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			backoff := retryAfter(resp, attempt)
+			l.Debug(
+				"trello-connector: rate limited, retrying",
+				zap.String("path", c.redact(urlAddress.Path)),
+				zap.Int("attempt", attempt+1),
+				zap.Duration("backoff", backoff),
+			)
+			if err := sleep(ctx, backoff); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		annotation := annotations.Annotations{}
+		if resp != nil {
+			if desc, err := c.limiter.description(resp); err == nil {
+				annotation.WithRateLimiting(desc)
+			} else {
+				return nil, annotation, err
+			}
+
+			return resp.Header, annotation, nil
+		}
+
+		return nil, nil, nil
+	}
+}
+
+// send makes a single request. Requests are built again for every attempt, since a request body can only be read once.
+func (c *TrelloClient) send(
+	ctx context.Context,
+	method string,
+	urlAddress *url.URL,
+	res interface{},
+	reqOptions []uhttp.RequestOption,
+) (*http.Response, error) {
 	req, err := c.wrapper.NewRequest(
 		ctx,
 		method,
 		urlAddress,
-		uhttp.WithContentTypeJSONHeader(),
-		uhttp.WithAcceptJSONHeader(),
+		reqOptions...,
 	)
 
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
+	var resp *http.Response
 	switch method {
 	case http.MethodGet, http.MethodPut, http.MethodPost:
 		var doOptions []uhttp.DoOption
@@ -275,34 +1071,37 @@ func (c *TrelloClient) doRequest(
 			doOptions = append(doOptions, uhttp.WithResponse(&res))
 		}
 		resp, err = c.wrapper.Do(req, doOptions...)
-		if resp != nil {
-			defer resp.Body.Close()
-		}
 	case http.MethodDelete:
 		resp, err = c.wrapper.Do(req)
-		if resp != nil {
-			defer resp.Body.Close()
-		}
 	}
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	return resp, err
+}
+
+// authorizationHeader returns the OAuth Authorization header Trello accepts in place of the key and token query
+// parameters, so the credentials don't end up in logged or echoed URLs.
+// https://developer.atlassian.com/cloud/trello/guides/rest-api/authorization/#passing-token-and-key-in-api-requests
+func (c *TrelloClient) authorizationHeader() string {
+	return fmt.Sprintf(`OAuth oauth_consumer_key="%s", oauth_token="%s"`, c.ApiKey, c.ApiToken)
+}
 
+// withQueryParams adds the query parameters to the endpoint URL, keeping the ones it already has.
+func withQueryParams(endpointUrl string, params url.Values) (string, error) {
+	urlAddress, err := url.Parse(endpointUrl)
 	if err != nil {
-		return nil, nil, err
+		return "", err
 	}
 
-	annotation := annotations.Annotations{}
-	if resp != nil {
-		if desc, err := ratelimit.ExtractRateLimitData(resp.StatusCode, &resp.Header); err == nil {
-			annotation.WithRateLimiting(desc)
-		} else {
-			return nil, annotation, err
+	query := urlAddress.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
 		}
-
-		return resp.Header, annotation, nil
 	}
+	urlAddress.RawQuery = query.Encode()
 
-	return nil, nil, err
-}
-
-func authorizeEndpointUrl(c *TrelloClient, endpointUrl string) string {
-	return endpointUrl + "?key=" + c.ApiKey + "&token=" + c.ApiToken
+	return urlAddress.String(), nil
 }
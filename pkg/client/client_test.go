@@ -1,7 +1,18 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestNewTrelloClient(t *testing.T) {
@@ -26,3 +37,142 @@ func TestTrelloClient_AddCredentials(t *testing.T) {
 		t.Errorf("Set API token failed. Expected %s, got %s", mockApiToken, client.ApiToken)
 	}
 }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Tests that requests answered with 429 are retried, and that the rate limit annotations report the tighter of the
+// API key and token budgets.
+// https://developer.atlassian.com/cloud/trello/guides/rest-api/rate-limits/
+func TestTrelloClient_RateLimit(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = time.Second }()
+
+	requests := 0
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"id": "ea960e6c-f613-4bed-8852-ab012603915b", "username": "tester1"}`)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		response.Header.Set("X-Rate-Limit-Api-Key-Interval-Ms", "10000")
+		response.Header.Set("X-Rate-Limit-Api-Key-Max", "300")
+		response.Header.Set("X-Rate-Limit-Api-Key-Remaining", "250")
+		response.Header.Set("X-Rate-Limit-Api-Token-Interval-Ms", "10000")
+		response.Header.Set("X-Rate-Limit-Api-Token-Max", "100")
+		response.Header.Set("X-Rate-Limit-Api-Token-Remaining", "40")
+
+		if requests == 1 {
+			response.StatusCode = http.StatusTooManyRequests
+			response.Status = "429 Too Many Requests"
+			response.Header.Set("Content-Type", "text/plain")
+			response.Header.Set("X-Rate-Limit-Api-Token-Interval-Ms", "10")
+			response.Header.Set("X-Rate-Limit-Api-Token-Remaining", "0")
+			response.Body = io.NopCloser(strings.NewReader("API_TOKEN_LIMIT_EXCEEDED"))
+		}
+
+		return response, nil
+	})
+
+	client := NewClient("api-key", "api-token", []string{}, uhttp.NewBaseHttpClient(&http.Client{Transport: transport}))
+
+	member, annos, err := client.GetMemberDetails(context.Background(), "me")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected the rate limited request to be retried once, got %d requests", requests)
+	}
+
+	if member == nil || member.Username != "tester1" {
+		t.Errorf("Expected member tester1, got %+v", member)
+	}
+
+	description := &v2.RateLimitDescription{}
+	ok, err := annos.Pick(description)
+	if err != nil || !ok {
+		t.Fatalf("Expected a rate limit annotation, got %v", err)
+	}
+
+	if description.Limit != 100 || description.Remaining != 40 {
+		t.Errorf("Expected the token budget 40/100, got %d/%d", description.Remaining, description.Limit)
+	}
+	if description.Status != v2.RateLimitDescription_STATUS_OK {
+		t.Errorf("Expected status OK, got %s", description.Status)
+	}
+}
+
+// Tests that a request is retried at most maxRateLimitRetries times before the 429 is returned.
+func TestTrelloClient_RateLimit_GivesUp(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = time.Second }()
+
+	requests := 0
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		response := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Status:     "429 Too Many Requests",
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader("API_KEY_LIMIT_EXCEEDED")),
+		}
+		response.Header.Set("X-Rate-Limit-Api-Key-Interval-Ms", "10")
+		response.Header.Set("X-Rate-Limit-Api-Key-Max", "300")
+		response.Header.Set("X-Rate-Limit-Api-Key-Remaining", "0")
+		return response, nil
+	})
+
+	client := NewClient("api-key", "api-token", []string{}, uhttp.NewBaseHttpClient(&http.Client{Transport: transport}))
+
+	_, _, err := client.GetMemberDetails(context.Background(), "me")
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected an unavailable error, got %v", err)
+	}
+
+	if requests != maxRateLimitRetries+1 {
+		t.Errorf("Expected %d requests, got %d", maxRateLimitRetries+1, requests)
+	}
+}
+
+// Tests that the API token is redacted from the errors of the requests carrying it in their path.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-get
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-webhooks-get
+func TestTrelloClient_TokenRedacted(t *testing.T) {
+	const apiToken = "0123456789abcdef-secret-token"
+
+	var paths []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		return nil, errors.New("connection refused")
+	})
+
+	client := NewClient("api-key", apiToken, []string{}, uhttp.NewBaseHttpClient(&http.Client{Transport: transport}))
+	ctx := context.Background()
+
+	_, _, err := client.GetToken(ctx, apiToken)
+	if err == nil {
+		t.Fatal("Expected an error getting the token")
+	}
+	if strings.Contains(err.Error(), apiToken) {
+		t.Errorf("Expected the token to be redacted, got %v", err)
+	}
+
+	_, _, err = client.ListWebhooks(ctx)
+	if err == nil {
+		t.Fatal("Expected an error listing webhooks")
+	}
+	if strings.Contains(err.Error(), apiToken) {
+		t.Errorf("Expected the token to be redacted, got %v", err)
+	}
+
+	expectedPaths := []string{"/1/tokens/" + apiToken, "/1/tokens/" + apiToken + "/webhooks"}
+	if strings.Join(paths, ",") != strings.Join(expectedPaths, ",") {
+		t.Errorf("Expected requests to %v, got %v", expectedPaths, paths)
+	}
+}
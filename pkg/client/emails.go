@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// LoadEmailMapping reads a CSV file of username,email rows. Lines starting with # are ignored.
+func LoadEmailMapping(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("trello-connector: failed to open email mapping file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	emails := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("trello-connector: failed to read email mapping file: %w", err)
+		}
+
+		username, email := strings.ToLower(strings.TrimSpace(record[0])), strings.TrimSpace(record[1])
+		if username == "" || email == "" {
+			continue
+		}
+		emails[username] = email
+	}
+
+	return emails, nil
+}
+
+// setEmails fills in the email of the users that don't have one, from the emails the token can see or, failing
+// that, the username to email mapping.
+func (c *TrelloClient) setEmails(ctx context.Context, users []User) {
+	emails := c.memberEmails(ctx)
+
+	for i := range users {
+		if users[i].Email != "" {
+			continue
+		}
+
+		if email, ok := emails[users[i].ID]; ok {
+			users[i].Email = email
+		} else if email, ok := c.EmailsByUsername[strings.ToLower(users[i].Username)]; ok {
+			users[i].Email = email
+		}
+	}
+}
+
+// memberEmails returns the emails the token can see by member ID: the email of the token's own member and, when an
+// enterprise is configured, the emails of its managed members. They are fetched once per sync; failures only mean
+// fewer emails, so they are logged rather than returned.
+func (c *TrelloClient) memberEmails(ctx context.Context) map[string]string {
+	l := ctxzap.Extract(ctx)
+
+	c.emailsMutex.Lock()
+	defer c.emailsMutex.Unlock()
+
+	if c.emails != nil {
+		return c.emails
+	}
+
+	emails := make(map[string]string)
+
+	self, _, err := c.GetMemberDetails(ctx, "me")
+	if err != nil {
+		l.Warn("trello-connector: failed to get the email of the token's member", zap.Error(err))
+	} else if self != nil && self.Email != "" {
+		emails[self.ID] = self.Email
+	}
+
+	if c.EnterpriseID != "" {
+		members, _, err := c.ListEnterpriseMembers(ctx, c.EnterpriseID)
+		if err != nil {
+			l.Warn("trello-connector: failed to get the emails of enterprise members", zap.Error(err))
+		}
+		for _, member := range members {
+			if member.Email != "" {
+				emails[member.ID] = member.Email
+			}
+		}
+	}
+
+	c.emails = emails
+
+	return emails
+}
@@ -1,16 +1,230 @@
 package client
 
+import (
+	"strconv"
+	"time"
+)
+
 type PaginationVars struct {
 	Size uint
 	Page uint
 }
 
 type User struct {
-	ID         string `json:"id"`
-	MemberID   string `json:"idMember"`
-	Name       string `json:"fullName"`
-	Username   string `json:"username"`
-	MemberType string `json:"memberType"`
+	ID             string     `json:"id"`
+	MemberID       string     `json:"idMember"`
+	Name           string     `json:"fullName"`
+	Username       string     `json:"username"`
+	Email          string     `json:"email"`
+	AvatarUrl      string     `json:"avatarUrl"`
+	MemberType     string     `json:"memberType"`
+	Deactivated    bool       `json:"deactivated"`
+	Unconfirmed    bool       `json:"unconfirmed"`
+	LastActive     *time.Time `json:"lastActive"`
+	DateLastActive *time.Time `json:"dateLastActive"`
+}
+
+// LastActivity returns when the member was last active. Organization members report it as lastActive, while the
+// member endpoints report it as dateLastActive.
+func (u *User) LastActivity() *time.Time {
+	if u.LastActive != nil {
+		return u.LastActive
+	}
+
+	return u.DateLastActive
+}
+
+// CreatedAt returns when the member's account was created, derived from the member ID.
+func (u *User) CreatedAt() (time.Time, bool) {
+	return ObjectIDTime(u.ID)
+}
+
+// ObjectIDTime returns the creation time embedded in a Trello ID. Trello IDs are MongoDB object IDs, whose first
+// four bytes hold the creation time in seconds since the Unix epoch.
+func ObjectIDTime(id string) (time.Time, bool) {
+	if len(id) != 24 {
+		return time.Time{}, false
+	}
+
+	seconds, err := strconv.ParseUint(id[:8], 16, 32)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(seconds), 0).UTC(), true
+}
+
+// Membership is a board or organization membership with the member details requested inline.
+type Membership struct {
+	ID          string `json:"id"`
+	MemberID    string `json:"idMember"`
+	MemberType  string `json:"memberType"`
+	Deactivated bool   `json:"deactivated"`
+	Unconfirmed bool   `json:"unconfirmed"`
+	Member      *User  `json:"member"`
+}
+
+type memberTypeBody struct {
+	Type string `json:"type"`
+}
+
+type createBoardBody struct {
+	Name            string `json:"name"`
+	Description     string `json:"desc,omitempty"`
+	IdOrganization  string `json:"idOrganization"`
+	PermissionLevel string `json:"prefs_permissionLevel,omitempty"`
+}
+
+type closeBoardBody struct {
+	Closed bool `json:"closed"`
+}
+
+type deactivatedBody struct {
+	Value bool `json:"value"`
+}
+
+type inviteMemberBody struct {
+	Email    string `json:"email"`
+	FullName string `json:"fullName"`
+	Type     string `json:"type"`
+}
+
+type createWebhookBody struct {
+	IdModel     string `json:"idModel"`
+	CallbackURL string `json:"callbackURL"`
+	Description string `json:"description,omitempty"`
+}
+
+type OrganizationMembers struct {
+	ID          string `json:"id"`
+	Members     []User `json:"members"`
+	Memberships []User `json:"memberships"`
+}
+
+// invitedMember returns the member created by an invitation: the member whose membership wasn't among the
+// memberships held before the invitation. Invitations create unconfirmed memberships, so those are preferred when
+// several members joined in the meantime. The member carries the status and type of its new membership.
+func (o *OrganizationMembers) invitedMember(previousMemberIDs map[string]bool) *User {
+	var invited *User
+	for _, membership := range o.Memberships {
+		if previousMemberIDs[membership.MemberID] {
+			continue
+		}
+		if invited == nil || (membership.Unconfirmed && !invited.Unconfirmed) {
+			membershipCopy := membership
+			invited = &membershipCopy
+		}
+	}
+	if invited == nil {
+		return nil
+	}
+
+	for _, member := range o.Members {
+		if member.ID == invited.MemberID {
+			memberCopy := member
+			memberCopy.Unconfirmed = invited.Unconfirmed
+			memberCopy.MemberType = invited.MemberType
+			return &memberCopy
+		}
+	}
+
+	return nil
+}
+
+// Token is an API token minted by a member for an application, along with the models it grants access to.
+type Token struct {
+	ID          string            `json:"id"`
+	Identifier  string            `json:"identifier"`
+	MemberID    string            `json:"idMember"`
+	DateCreated time.Time         `json:"dateCreated"`
+	DateExpires *time.Time        `json:"dateExpires"`
+	Permissions []TokenPermission `json:"permissions"`
+}
+
+// TokenPermission is the access a token grants to a model. A model ID of "*" covers every model of the type.
+type TokenPermission struct {
+	ModelID   string `json:"idModel"`
+	ModelType string `json:"modelType"`
+	Read      bool   `json:"read"`
+	Write     bool   `json:"write"`
+}
+
+// CanRead reports whether the token can read the models of the given type.
+func (t *Token) CanRead(modelType string) bool {
+	for _, permission := range t.Permissions {
+		if permission.ModelType == modelType && permission.Read {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Expired reports whether the token expired before the given time. Tokens without an expiry never expire.
+func (t *Token) Expired(now time.Time) bool {
+	return t.DateExpires != nil && t.DateExpires.Before(now)
+}
+
+// Action is an entry of the activity log of a board or organization.
+type Action struct {
+	ID              string     `json:"id"`
+	Type            string     `json:"type"`
+	Date            time.Time  `json:"date"`
+	MemberCreatorID string     `json:"idMemberCreator"`
+	Data            ActionData `json:"data"`
+	Member          *User      `json:"member"`
+}
+
+// ActionData holds the models an action refers to. Only the fields of membership actions are kept.
+type ActionData struct {
+	IdMember      string       `json:"idMember"`
+	IdMemberAdded string       `json:"idMemberAdded"`
+	MemberType    string       `json:"memberType"`
+	Board         *ActionModel `json:"board"`
+	Organization  *ActionModel `json:"organization"`
+}
+
+type ActionModel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// MemberID returns the member the action was performed on.
+func (a *Action) MemberID() string {
+	switch {
+	case a.Member != nil && a.Member.ID != "":
+		return a.Member.ID
+	case a.Data.IdMemberAdded != "":
+		return a.Data.IdMemberAdded
+	default:
+		return a.Data.IdMember
+	}
+}
+
+// ActionsQuery filters the actions of a board or organization. Since and Before accept an action ID or a date, and
+// actions are returned newest first.
+type ActionsQuery struct {
+	Filter []string
+	Since  string
+	Before string
+	Limit  int
+}
+
+// Webhook posts the actions of a model to a callback URL.
+type Webhook struct {
+	ID          string `json:"id"`
+	IdModel     string `json:"idModel"`
+	CallbackURL string `json:"callbackURL"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+}
+
+type Enterprise struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	DisplayName     string   `json:"displayName"`
+	IdAdmins        []string `json:"idAdmins"`
+	IdOrganizations []string `json:"idOrganizations"`
 }
 
 type Organization struct {
@@ -18,6 +232,7 @@ type Organization struct {
 	DisplayName     string `json:"displayName"`
 	Name            string `json:"name"`
 	Description     string `json:"desc"`
+	IdEnterprise    string `json:"idEnterprise"`
 	DescriptionData struct {
 		Emoji struct{} `json:"emoji"`
 	} `json:"descData"`
@@ -52,7 +267,7 @@ type Preferences struct {
 	Background            string      `json:"background"`
 	BackgroundColor       string      `json:"backgroundColor"`
 	BackgroundDarkColor   interface{} `json:"backgroundDarkColor"`
-	BackgroundImage       interface{} `json:"backgroundImage"`
+	BackgroundImage       string      `json:"backgroundImage"`
 	BackgroundDarkImage   interface{} `json:"backgroundDarkImage"`
 	BackgroundImageScaled interface{} `json:"backgroundImageScaled"`
 	BackgroundTile        bool        `json:"backgroundTile"`
@@ -68,18 +283,19 @@ type Preferences struct {
 }
 
 type Board struct {
-	ID             string      `json:"id"`
-	Name           string      `json:"name"`
-	Description    string      `json:"desc"`
-	DescData       interface{} `json:"descData"`
-	Closed         bool        `json:"closed"`
-	IdOrganization string      `json:"idOrganization"`
-	IdEnterprise   interface{} `json:"idEnterprise"`
-	Pinned         bool        `json:"pinned"`
-	Url            string      `json:"url"`
-	ShortUrl       string      `json:"shortUrl"`
-	Preferences    Preferences `json:"prefs"`
-	LabelNames     struct {
+	ID              string      `json:"id"`
+	Name            string      `json:"name"`
+	Description     string      `json:"desc"`
+	DescData        interface{} `json:"descData"`
+	Closed          bool        `json:"closed"`
+	IdOrganization  string      `json:"idOrganization"`
+	IdEnterprise    string      `json:"idEnterprise"`
+	IdMemberCreator string      `json:"idMemberCreator"`
+	Pinned          bool        `json:"pinned"`
+	Url             string      `json:"url"`
+	ShortUrl        string      `json:"shortUrl"`
+	Preferences     Preferences `json:"prefs"`
+	LabelNames      struct {
 		Green       string `json:"green"`
 		Yellow      string `json:"yellow"`
 		Orange      string `json:"orange"`
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/ratelimit"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Trello limits requests per API key and per token, each over its own interval, and reports both budgets on every
+// response. https://developer.atlassian.com/cloud/trello/guides/rest-api/rate-limits/
+const (
+	apiKeyRateLimitPrefix   = "X-Rate-Limit-Api-Key-"
+	apiTokenRateLimitPrefix = "X-Rate-Limit-Api-Token-"
+
+	// defaultRateLimitInterval is the interval Trello documents for both budgets, used until a response reports it.
+	defaultRateLimitInterval = 10 * time.Second
+
+	// maxRateLimitRetries is how many times a request answered with 429 is retried before giving up.
+	maxRateLimitRetries = 3
+)
+
+// retryBackoff is the wait before the first retry of a request answered with 429. It doubles on each retry, unless
+// the response says how long to wait.
+var retryBackoff = time.Second
+
+// rateLimitBudget is what's left of one of Trello's request budgets in the current interval.
+type rateLimitBudget struct {
+	max         int64
+	remaining   int64
+	interval    time.Duration
+	windowStart time.Time
+}
+
+func (b *rateLimitBudget) resetAt() time.Time {
+	return b.windowStart.Add(b.interval)
+}
+
+// update reads the budget from the response headers with the given prefix. It returns false when the response
+// doesn't report the budget.
+func (b *rateLimitBudget) update(header http.Header, prefix string, now time.Time) bool {
+	remaining, err := strconv.ParseInt(header.Get(prefix+"Remaining"), 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if limit, err := strconv.ParseInt(header.Get(prefix+"Max"), 10, 64); err == nil {
+		b.max = limit
+	}
+
+	b.interval = defaultRateLimitInterval
+	if interval, err := strconv.ParseInt(header.Get(prefix+"Interval-Ms"), 10, 64); err == nil && interval > 0 {
+		b.interval = time.Duration(interval) * time.Millisecond
+	}
+
+	// Note: Trello doesn't report when the interval resets, so a new interval is assumed to start when the budget
+	// goes back up.
+	if b.windowStart.IsZero() || remaining > b.remaining || now.After(b.resetAt()) {
+		b.windowStart = now
+	}
+	b.remaining = remaining
+
+	return true
+}
+
+// rateLimiter tracks the API key and token budgets Trello reports, so requests wait for the next interval instead
+// of being answered with 429.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	apiKey   rateLimitBudget
+	apiToken rateLimitBudget
+	seen     bool
+}
+
+// wait blocks until both budgets have requests left, or the context is done.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	r.mutex.Lock()
+	var until time.Time
+	for _, budget := range []*rateLimitBudget{&r.apiKey, &r.apiToken} {
+		if budget.max > 0 && budget.remaining <= 0 && budget.resetAt().After(until) {
+			until = budget.resetAt()
+		}
+	}
+	r.mutex.Unlock()
+
+	return sleep(ctx, time.Until(until))
+}
+
+// update records the budgets reported by a response.
+func (r *rateLimiter) update(header http.Header) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	keyReported := r.apiKey.update(header, apiKeyRateLimitPrefix, now)
+	tokenReported := r.apiToken.update(header, apiTokenRateLimitPrefix, now)
+	r.seen = r.seen || keyReported || tokenReported
+}
+
+// description returns the budget closest to running out, falling back to the standard rate limit headers when
+// Trello's aren't present.
+func (r *rateLimiter) description(resp *http.Response) (*v2.RateLimitDescription, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.seen {
+		return ratelimit.ExtractRateLimitData(resp.StatusCode, &resp.Header)
+	}
+
+	budget := &r.apiToken
+	if budget.max == 0 || (r.apiKey.max > 0 && r.apiKey.remaining*budget.max < budget.remaining*r.apiKey.max) {
+		budget = &r.apiKey
+	}
+
+	rateLimitStatus := v2.RateLimitDescription_STATUS_OK
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateLimitStatus = v2.RateLimitDescription_STATUS_OVERLIMIT
+	}
+
+	return &v2.RateLimitDescription{
+		Status:    rateLimitStatus,
+		Limit:     budget.max,
+		Remaining: budget.remaining,
+		ResetAt:   timestamppb.New(budget.resetAt()),
+	}, nil
+}
+
+// retryAfter returns how long to wait before retrying a request answered with 429: the Retry-After header when
+// present, otherwise an exponential backoff.
+func retryAfter(resp *http.Response, attempt int) time.Duration {
+	if seconds, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return retryBackoff << attempt
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
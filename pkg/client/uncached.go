@@ -0,0 +1,161 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/grpc/codes"
+)
+
+// getUncachedResourcesFromAPI reads a resource past the response cache, for reads that must reflect changes made
+// moments before, like the membership checks of a grant or revoke, or polling for new actions.
+func (c *TrelloClient) getUncachedResourcesFromAPI(
+	ctx context.Context,
+	urlAddress string,
+	res any,
+) (annotations.Annotations, error) {
+	_, annotation, err := c.doRequestWith(ctx, c.sendUncached, http.MethodGet, urlAddress, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// sendUncached makes a single request with the wrapped http client, so the response is neither served from nor
+// stored in the cache. Unsuccessful responses are turned into the same errors the wrapper returns.
+func (c *TrelloClient) sendUncached(
+	ctx context.Context,
+	method string,
+	urlAddress *url.URL,
+	res interface{},
+	reqOptions []uhttp.RequestOption,
+) (*http.Response, error) {
+	return sendWith(ctx, c.wrapper, c.wrapper.HttpClient, method, urlAddress, res, reqOptions)
+}
+
+// sendQuietly makes a single request past the response cache with an http client that doesn't log requests, for
+// endpoints that carry the API token in their path. The token is redacted from the errors returned.
+func (c *TrelloClient) sendQuietly(
+	ctx context.Context,
+	method string,
+	urlAddress *url.URL,
+	res interface{},
+	reqOptions []uhttp.RequestOption,
+) (*http.Response, error) {
+	httpClient := c.quietHttpClient
+	if httpClient == nil {
+		httpClient = c.wrapper.HttpClient
+	}
+
+	resp, err := sendWith(ctx, c.wrapper, httpClient, method, urlAddress, res, reqOptions)
+	if err != nil {
+		return resp, c.redactToken(err)
+	}
+
+	return resp, nil
+}
+
+// redactToken removes the API token from the URL of a failed request.
+func (c *TrelloClient) redactToken(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+
+	return &url.Error{
+		Op:  urlErr.Op,
+		URL: c.redact(urlErr.URL),
+		Err: urlErr.Err,
+	}
+}
+
+// redact replaces the API token in a URL or path.
+func (c *TrelloClient) redact(value string) string {
+	if c.ApiToken == "" {
+		return value
+	}
+
+	return strings.ReplaceAll(value, c.ApiToken, "REDACTED")
+}
+
+func sendWith(
+	ctx context.Context,
+	wrapper *uhttp.BaseHttpClient,
+	httpClient *http.Client,
+	method string,
+	urlAddress *url.URL,
+	res interface{},
+	reqOptions []uhttp.RequestOption,
+) (*http.Response, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	req, err := wrapper.NewRequest(ctx, method, urlAddress, reqOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	if err := responseError(resp); err != nil {
+		return resp, err
+	}
+
+	if res != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, res); err != nil {
+			return resp, fmt.Errorf("trello-connector: failed to decode response: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// responseError maps an unsuccessful response to the error uhttp returns for its status code.
+func responseError(resp *http.Response) error {
+	var code codes.Code
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout:
+		code = codes.DeadlineExceeded
+	case resp.StatusCode == http.StatusTooManyRequests:
+		code = codes.Unavailable
+	case resp.StatusCode == http.StatusNotFound:
+		code = codes.NotFound
+	case resp.StatusCode == http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case resp.StatusCode == http.StatusForbidden:
+		code = codes.PermissionDenied
+	case resp.StatusCode == http.StatusConflict:
+		code = codes.AlreadyExists
+	case resp.StatusCode == http.StatusNotImplemented:
+		code = codes.Unimplemented
+	case resp.StatusCode >= 500 && resp.StatusCode <= 599:
+		code = codes.Unavailable
+	default:
+		code = codes.Unknown
+	}
+
+	return uhttp.WrapErrorsWithRateLimitInfo(code, resp)
+}
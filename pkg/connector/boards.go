@@ -12,42 +12,91 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	"github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 type boardBuilder struct {
-	resourceType     *v2.ResourceType
-	client           *client.TrelloClient
-	memberships      map[string][]client.User
-	membershipsMutex sync.RWMutex
+	resourceType        *v2.ResourceType
+	client              *client.TrelloClient
+	deleteBoards        bool
+	includeClosedBoards bool
+	personalBoards      bool
+	memberships         map[string][]client.User
+	membershipsMutex    sync.RWMutex
+	syncedMembers       map[string]bool
+	syncedMembersMutex  sync.Mutex
+	scope               *syncScope
 }
 
+// boardViewer is held by everyone who can view a board because of its visibility rather than its membership. For
+// boards visible to the organization or to the public, it is granted to the parent organization and expands to the
+// organization's active members. Public boards are modelled as exposed to the organization only, since anyone else
+// viewing them isn't a synced principal.
+const boardViewer = "viewer"
+
 func (o *boardBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 	return boardResourceType
 }
 
-func (o *boardBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
-	var resources []*v2.Resource
+// List returns the boards of the parent organization or, when personal boards are synced, the personal boards the
+// parent user owns. Boards are only listed under their parent. Closed (archived) boards are skipped unless closed
+// boards are included.
+func (o *boardBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
 
-	// Note: Trello API doesn't support pagination for boards by organization queries.
-	boards, annotation, err := o.client.ListBoards(ctx)
+	var (
+		resources  []*v2.Resource
+		annotation annotations.Annotations
+		err        error
+	)
+	switch parentResourceID.ResourceType {
+	case organizationResourceType.Id:
+		resources, annotation, err = o.listOrganizationBoards(ctx, parentResourceID)
+	case userResourceType.Id:
+		if !o.personalBoards {
+			return nil, "", nil, nil
+		}
+		resources, annotation, err = o.listPersonalBoards(ctx, parentResourceID)
+	default:
+		return nil, "", nil, nil
+	}
 	if err != nil {
 		return nil, "", nil, err
 	}
 
+	return resources, "", annotation, nil
+}
+
+// listOrganizationBoards returns the boards of the organization.
+// Note: Trello API doesn't support pagination for boards by organization queries.
+func (o *boardBuilder) listOrganizationBoards(ctx context.Context, organizationResourceID *v2.ResourceId) ([]*v2.Resource, annotations.Annotations, error) {
+	boards, annotation, err := o.client.ListBoards(ctx, organizationResourceID.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resources []*v2.Resource
 	for _, board := range boards {
-		boardCopy := board
-		parentResourceId, err := resource.NewResourceID(organizationResourceType, board.IdOrganization)
-		if err != nil {
-			return nil, "", nil, err
+		if board.Closed && !o.includeClosedBoards {
+			continue
 		}
-		boardResource, err := parseIntoBoardResource(ctx, &boardCopy, parentResourceId)
+
+		if !o.scope.board(board.ID) {
+			continue
+		}
+
+		boardCopy := board
+		boardResource, err := parseIntoBoardResource(ctx, &boardCopy, organizationResourceID)
 		if err != nil {
-			return nil, "", nil, err
+			return nil, nil, err
 		}
 		resources = append(resources, boardResource)
 	}
 
-	return resources, "", annotation, nil
+	return resources, annotation, nil
 }
 
 func parseIntoBoardResource(_ context.Context, board *client.Board, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
@@ -61,12 +110,19 @@ func parseIntoBoardResource(_ context.Context, board *client.Board, parentResour
 		"comments":         board.Preferences.Comments,
 		"invitations":      board.Preferences.Invitations,
 		"self_join":        board.Preferences.SelfJoin,
+		"enterprise_id":    board.IdEnterprise,
+		"closed":           board.Closed,
 	}
 
 	groupTraits := []resource.GroupTraitOption{
 		resource.WithGroupProfile(profile),
 	}
 
+	// Note: boards with a plain color background have no background image.
+	if board.Preferences.BackgroundImage != "" {
+		groupTraits = append(groupTraits, resource.WithGroupIcon(&v2.AssetRef{Id: board.Preferences.BackgroundImage}))
+	}
+
 	displayName := board.Name
 
 	ret, err := resource.NewGroupResource(
@@ -83,130 +139,386 @@ func parseIntoBoardResource(_ context.Context, board *client.Board, parentResour
 	return ret, nil
 }
 
-func (o *boardBuilder) Entitlements(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+func (o *boardBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
 	var entitlements []*v2.Entitlement
+	for _, memberType := range memberTypes {
+		assigmentOptions := []entitlement.EntitlementOption{
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDescription(fmt.Sprintf("Member type %s for board %s in Trello", memberType, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s Board %s", resource.DisplayName, memberType)),
+		}
+
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, memberType, assigmentOptions...))
+	}
+
+	if viewableByOrganization(resource) {
+		assigmentOptions := []entitlement.EntitlementOption{
+			entitlement.WithGrantableTo(organizationResourceType),
+			entitlement.WithDescription(fmt.Sprintf(
+				"Can view board %s in Trello through its visibility, as an active member of its organization. Public boards are modelled as exposed to the organization only",
+				resource.DisplayName,
+			)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s Board %s", resource.DisplayName, boardViewer)),
+		}
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, boardViewer, assigmentOptions...))
+	}
+
+	return entitlements, "", nil, nil
+}
+
+func (o *boardBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	var boardID = resource.Id.Resource
+
+	if !o.scope.board(boardID) {
+		return nil, "", nil, nil
+	}
 
-	// Note: Trello API only support getting boards by ID so it is not a bulk operation. Pagination is not needed.
-	board, _, err := o.client.GetBoardDetails(ctx, resource.Id.Resource)
+	// Note: Trello API doesn't support pagination for board membership queries.
+	err := o.GetMemberships(ctx, boardID)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	// Self join
-	selfJoin := "self join disabled"
-	if board.Preferences.SelfJoin {
-		selfJoin = "self join enabled"
+	// Note: grants on closed boards are labelled, so they can be found and revoked together.
+	closed := boardClosed(resource)
+
+	o.membershipsMutex.RLock()
+	memberships := o.memberships[boardID]
+	o.membershipsMutex.RUnlock()
+
+	for _, membership := range memberships {
+		userResource, _ := parseIntoUserResource(ctx, &membership, resource.Id)
+		grantOptions := []grant.GrantOption{grant.WithAnnotation(&v2.V1Identifier{
+			Id: fmt.Sprintf("board-grant:%s:%s:%s", resource.Id.Resource, membership.MemberID, membership.MemberType),
+		})}
+		if closed {
+			grantOptions = append(grantOptions, grant.WithGrantMetadata(map[string]interface{}{"board_closed": true}))
+		}
+		membershipGrant := grant.NewGrant(resource, membership.MemberType, userResource, grantOptions...)
+		grants = append(grants, membershipGrant)
 	}
-	assigmentOptions := []entitlement.EntitlementOption{
-		entitlement.WithGrantableTo(userResourceType),
-		entitlement.WithDescription(fmt.Sprintf("Is %s for board %s in Trello", selfJoin, resource.DisplayName)),
-		entitlement.WithDisplayName(fmt.Sprintf("%s Board %s", resource.DisplayName, selfJoin)),
+
+	// Note: every active member of the organization can view boards visible to the organization or to the public, so
+	// the viewer grant expands to the organization's active membership. Deactivated members can't view them.
+	if viewableByOrganization(resource) {
+		organizationResource := &v2.Resource{Id: resource.ParentResourceId}
+
+		viewerGrant := grant.NewGrant(resource, boardViewer, organizationResource.Id, grant.WithAnnotation(
+			&v2.GrantExpandable{
+				EntitlementIds: []string{entitlement.NewEntitlementID(organizationResource, activeMember)},
+				Shallow:        true,
+			},
+			&v2.V1Identifier{
+				Id: fmt.Sprintf("board-grant:%s:%s:%s", resource.Id.Resource, organizationResource.Id.Resource, boardViewer),
+			},
+		))
+		grants = append(grants, viewerGrant)
 	}
-	entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, selfJoin, assigmentOptions...))
 
-	// Voting
-	voting := fmt.Sprintf("Voting %s", board.Preferences.Voting)
-	assigmentOptions = []entitlement.EntitlementOption{
-		entitlement.WithGrantableTo(userResourceType),
-		entitlement.WithDescription(fmt.Sprintf("%s for board %s in Trello", voting, resource.DisplayName)),
-		entitlement.WithDisplayName(fmt.Sprintf("%s Board %s", resource.DisplayName, voting)),
+	return grants, "", nil, nil
+}
+
+// Grant adds the principal to the board with the entitlement's member type. Members that already belong to the
+// board have their member type changed instead.
+func (o *boardBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		l.Warn(
+			"trello-connector: only users can be granted board membership",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, nil, fmt.Errorf("trello-connector: only users can be granted board membership")
 	}
-	entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, voting, assigmentOptions...))
 
-	// Comments
-	comments := fmt.Sprintf("Comments %s", board.Preferences.Comments)
-	assigmentOptions = []entitlement.EntitlementOption{
-		entitlement.WithGrantableTo(userResourceType),
-		entitlement.WithDescription(fmt.Sprintf("%s for board %s in Trello", comments, resource.DisplayName)),
-		entitlement.WithDisplayName(fmt.Sprintf("%s Board %s", resource.DisplayName, comments)),
+	boardID := entitlement.Resource.Id.Resource
+	memberType, err := entitlementSlug(entitlement)
+	if err != nil {
+		return nil, nil, err
 	}
-	entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, comments, assigmentOptions...))
 
-	// Invitations
-	invitations := fmt.Sprintf("Invitations %s", board.Preferences.Invitations)
-	assigmentOptions = []entitlement.EntitlementOption{
-		entitlement.WithGrantableTo(userResourceType),
-		entitlement.WithDescription(fmt.Sprintf("%s for board %s in Trello", invitations, resource.DisplayName)),
-		entitlement.WithDisplayName(fmt.Sprintf("%s Board %s", resource.DisplayName, invitations)),
+	if memberType == boardViewer {
+		return nil, nil, fmt.Errorf("trello-connector: board viewers follow the board's visibility and can't be granted")
 	}
-	entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, invitations, assigmentOptions...))
 
-	return entitlements, "", nil, nil
+	membership, _, err := o.client.GetBoardMembership(ctx, boardID, principal.Id.Resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trello-connector: failed to get board membership: %w", err)
+	}
+
+	membershipGrant := grant.NewGrant(entitlement.Resource, memberType, principal.Id, grant.WithAnnotation(&v2.V1Identifier{
+		Id: fmt.Sprintf("board-grant:%s:%s:%s", boardID, principal.Id.Resource, memberType),
+	}))
+
+	if membership != nil && membership.MemberType == memberType {
+		return []*v2.Grant{membershipGrant}, annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	annotation, err := o.client.AddMemberToBoard(ctx, boardID, principal.Id.Resource, memberType)
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to add member to board",
+			zap.String("board_id", boardID),
+			zap.String("member_id", principal.Id.Resource),
+			zap.String("member_type", memberType),
+			zap.Error(err),
+		)
+		return nil, nil, fmt.Errorf("trello-connector: failed to add member to board: %w", err)
+	}
+
+	o.resetMemberships(boardID)
+
+	return []*v2.Grant{membershipGrant}, annotation, nil
 }
 
-func (o *boardBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
-	var grants []*v2.Grant
+// Revoke removes the principal from the board. A Trello member holds a single member type per board, so when the
+// revoked member type is the one the member currently holds, the whole membership is removed.
+func (o *boardBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		l.Warn(
+			"trello-connector: only users can have board membership revoked",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("trello-connector: only users can have board membership revoked")
+	}
 
-	var boardID = resource.Id.Resource
+	boardID := grant.Entitlement.Resource.Id.Resource
+	memberType, err := entitlementSlug(grant.Entitlement)
+	if err != nil {
+		return nil, err
+	}
 
-	// Note: Trello API only support getting boards by ID so it is not a bulk operation. Pagination is not needed.
-	board, _, err := o.client.GetBoardDetails(ctx, boardID)
+	membership, _, err := o.client.GetBoardMembership(ctx, boardID, principal.Id.Resource)
 	if err != nil {
-		return nil, "", nil, err
+		return nil, fmt.Errorf("trello-connector: failed to get board membership: %w", err)
 	}
-	err = o.GetMemberships(ctx, boardID)
+
+	if membership == nil || membership.MemberType != memberType {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	annotation, err := o.client.RemoveMemberFromBoard(ctx, boardID, principal.Id.Resource)
 	if err != nil {
-		return nil, "", nil, err
+		l.Error(
+			"trello-connector: failed to remove member from board",
+			zap.String("board_id", boardID),
+			zap.String("member_id", principal.Id.Resource),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("trello-connector: failed to remove member from board: %w", err)
 	}
 
-	for _, membership := range o.memberships[boardID] {
-		userResource, _ := parseIntoUserResource(ctx, &membership, resource.Id)
-		membershipType := membership.MemberType
-
-		// Self join
-		if board.Preferences.SelfJoin {
-			selfJoin := "self join enabled"
-			membershipGrant := grant.NewGrant(resource, selfJoin, userResource, grant.WithAnnotation(&v2.V1Identifier{
-				Id: fmt.Sprintf("board-grant:%s:%s:%s", resource.Id.Resource, membership.MemberID, selfJoin),
-			}))
-			grants = append(grants, membershipGrant)
+	o.resetMemberships(boardID)
+
+	return annotation, nil
+}
+
+// Create creates a board inside the parent organization from the resource's display name, description and
+// permission level. The permission level is read from the board profile and defaults to Trello's own default.
+func (o *boardBuilder) Create(ctx context.Context, boardResource *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	parentResourceID := boardResource.GetParentResourceId()
+	if parentResourceID == nil || parentResourceID.ResourceType != organizationResourceType.Id {
+		return nil, nil, fmt.Errorf("trello-connector: boards must be created inside an organization")
+	}
+
+	var permissionLevel string
+	if groupTrait, err := resource.GetGroupTrait(boardResource); err == nil {
+		permissionLevel, _ = resource.GetProfileStringValue(groupTrait.GetProfile(), "permission_level")
+	}
+
+	board, annotation, err := o.client.CreateBoard(ctx, parentResourceID.Resource, boardResource.DisplayName, boardResource.Description, permissionLevel)
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to create board",
+			zap.String("organization_id", parentResourceID.Resource),
+			zap.String("board_name", boardResource.DisplayName),
+			zap.Error(err),
+		)
+		return nil, nil, fmt.Errorf("trello-connector: failed to create board: %w", err)
+	}
+
+	ret, err := parseIntoBoardResource(ctx, board, parentResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ret, annotation, nil
+}
+
+// Delete closes (archives) the board, or permanently deletes it when board deletion is enabled.
+func (o *boardBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if resourceId.ResourceType != boardResourceType.Id {
+		return nil, fmt.Errorf("trello-connector: invalid resource type %s", resourceId.ResourceType)
+	}
+
+	var (
+		annotation annotations.Annotations
+		err        error
+	)
+	if o.deleteBoards {
+		annotation, err = o.client.DeleteBoard(ctx, resourceId.Resource)
+	} else {
+		annotation, err = o.client.CloseBoard(ctx, resourceId.Resource)
+	}
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to delete board",
+			zap.String("board_id", resourceId.Resource),
+			zap.Bool("permanent", o.deleteBoards),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("trello-connector: failed to delete board: %w", err)
+	}
+
+	return annotation, nil
+}
+
+func newBoardBuilder(c *client.TrelloClient, deleteBoards, includeClosedBoards, personalBoards bool, scope *syncScope) *boardBuilder {
+	return &boardBuilder{
+		resourceType:        userResourceType,
+		client:              c,
+		deleteBoards:        deleteBoards,
+		includeClosedBoards: includeClosedBoards,
+		personalBoards:      personalBoards,
+		scope:               scope,
+	}
+}
+
+// listPersonalBoards returns the boards outside any organization that the member owns. A personal board is owned by
+// the member that created it or, when its creator isn't a synced member, by the first of its members that is. This
+// way a board shared between several synced members is synced once.
+func (o *boardBuilder) listPersonalBoards(ctx context.Context, memberResourceID *v2.ResourceId) ([]*v2.Resource, annotations.Annotations, error) {
+	memberID := memberResourceID.Resource
+
+	boards, annotation, err := o.client.ListPersonalBoards(ctx, memberID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	syncedMembers, err := o.listSyncedMembers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resources []*v2.Resource
+	for _, board := range boards {
+		if board.Closed && !o.includeClosedBoards {
+			continue
 		}
 
-		// Voting
-		if evaluateMembership(membershipType, board.Preferences.Voting) {
-			voting := fmt.Sprintf("Voting %s", board.Preferences.Voting)
-			membershipGrant := grant.NewGrant(resource, voting, userResource, grant.WithAnnotation(&v2.V1Identifier{
-				Id: fmt.Sprintf("board-grant:%s:%s:%s", resource.Id.Resource, membership.MemberID, voting),
-			}))
-			grants = append(grants, membershipGrant)
+		if !o.scope.board(board.ID) {
+			continue
 		}
 
-		// Comments
-		if evaluateMembership(membershipType, board.Preferences.Comments) {
-			comments := fmt.Sprintf("Comments %s", board.Preferences.Comments)
-			membershipGrant := grant.NewGrant(resource, comments, userResource, grant.WithAnnotation(&v2.V1Identifier{
-				Id: fmt.Sprintf("board-grant:%s:%s:%s", resource.Id.Resource, membership.MemberID, comments),
-			}))
-			grants = append(grants, membershipGrant)
+		if personalBoardOwner(&board, syncedMembers) != memberID {
+			continue
 		}
 
-		// Invitations
-		if evaluateMembership(membershipType, board.Preferences.Invitations) {
-			invitations := fmt.Sprintf("Invitations %s", board.Preferences.Invitations)
-			membershipGrant := grant.NewGrant(resource, invitations, userResource, grant.WithAnnotation(&v2.V1Identifier{
-				Id: fmt.Sprintf("board-grant:%s:%s:%s", resource.Id.Resource, membership.MemberID, invitations),
-			}))
-			grants = append(grants, membershipGrant)
+		boardCopy := board
+		boardResource, err := parseIntoBoardResource(ctx, &boardCopy, memberResourceID)
+		if err != nil {
+			return nil, nil, err
 		}
+		resources = append(resources, boardResource)
 	}
 
-	return grants, "", nil, nil
+	return resources, annotation, nil
 }
 
-func newBoardBuilder(c *client.TrelloClient) *boardBuilder {
-	return &boardBuilder{
-		resourceType: userResourceType,
-		client:       c,
+// personalBoardOwner returns the synced member a personal board is synced under.
+func personalBoardOwner(board *client.Board, syncedMembers map[string]bool) string {
+	if syncedMembers[board.IdMemberCreator] {
+		return board.IdMemberCreator
+	}
+
+	for _, membership := range board.Memberships {
+		if syncedMembers[membership.MemberID] {
+			return membership.MemberID
+		}
 	}
+
+	return ""
 }
 
-func evaluateMembership(membershipType, permission string) bool {
-	return (membershipType == "admin" && permission == "admins") || permission == "members"
+// listSyncedMembers returns the IDs of the members of the synced organizations. They are fetched once per sync.
+func (o *boardBuilder) listSyncedMembers(ctx context.Context) (map[string]bool, error) {
+	o.syncedMembersMutex.Lock()
+	defer o.syncedMembersMutex.Unlock()
+
+	if o.syncedMembers != nil {
+		return o.syncedMembers, nil
+	}
+
+	organizationIDs, err := o.client.ListOrganizationIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	syncedMembers := make(map[string]bool)
+	for _, organizationID := range organizationIDs {
+		users, _, err := o.client.ListUsers(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			syncedMembers[user.ID] = true
+		}
+	}
+
+	o.syncedMembers = syncedMembers
+
+	return syncedMembers, nil
+}
+
+// viewableByOrganization reports whether the board resource is in an organization and visible to its members or to
+// the public.
+func viewableByOrganization(boardResource *v2.Resource) bool {
+	if boardResource.GetParentResourceId().GetResourceType() != organizationResourceType.Id {
+		return false
+	}
+
+	groupTrait, err := resource.GetGroupTrait(boardResource)
+	if err != nil {
+		return false
+	}
+
+	permissionLevel, _ := resource.GetProfileStringValue(groupTrait.GetProfile(), "permission_level")
+
+	return permissionLevel == "org" || permissionLevel == "public"
+}
+
+// boardClosed reports whether the board resource was closed (archived) when it was synced.
+func boardClosed(boardResource *v2.Resource) bool {
+	groupTrait, err := resource.GetGroupTrait(boardResource)
+	if err != nil {
+		return false
+	}
+
+	return groupTrait.GetProfile().GetFields()["closed"].GetBoolValue()
+}
+
+func (o *boardBuilder) resetMemberships(boardID string) {
+	o.membershipsMutex.Lock()
+	defer o.membershipsMutex.Unlock()
+
+	delete(o.memberships, boardID)
 }
 
 func (o *boardBuilder) GetMemberships(ctx context.Context, boardID string) error {
-	o.membershipsMutex.RLock()
-	defer o.membershipsMutex.RUnlock()
+	o.membershipsMutex.Lock()
+	defer o.membershipsMutex.Unlock()
 
 	if o.memberships == nil {
 		o.memberships = make(map[string][]client.User)
@@ -9,9 +9,16 @@ import (
 	"strings"
 	"testing"
 
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/conductorone/baton-trello/pkg/client"
 	"github.com/conductorone/baton-trello/test"
+	"google.golang.org/protobuf/proto"
 )
 
 var expectedMemberships = [][]client.User{
@@ -46,7 +53,7 @@ func TestTrelloClient_GetBoards(t *testing.T) {
 
 	// Call GetBoards
 	ctx := context.Background()
-	result, nextOptions, err := testClient.ListBoards(ctx)
+	result, nextOptions, err := testClient.ListBoards(ctx, test.OrganizationIDs[0])
 
 	// Check for errors.
 	if err != nil {
@@ -69,12 +76,13 @@ func TestTrelloClient_GetBoards(t *testing.T) {
 			invitations = "admins"
 		}
 		expectedBoard := client.Board{
-			ID:             test.BoardIDs[index],
-			Name:           fmt.Sprintf("Test %d", index+1),
-			Closed:         false,
-			IdOrganization: test.OrganizationIDs[0],
-			Pinned:         false,
-			Url:            fmt.Sprintf("https://trello.com/b/test/test%d", index+1),
+			ID:              test.BoardIDs[index],
+			Name:            fmt.Sprintf("Test %d", index+1),
+			Closed:          false,
+			IdOrganization:  test.OrganizationIDs[0],
+			IdMemberCreator: test.UserIDs[0],
+			Pinned:          false,
+			Url:             fmt.Sprintf("https://trello.com/b/test/test%d", index+1),
 			Preferences: client.Preferences{
 				PermissionLevel:     "org",
 				HideVotes:           false,
@@ -134,7 +142,7 @@ func TestTrelloClient_GetBoards_RequestDetails(t *testing.T) {
 
 	// Call GetBoards.
 	ctx := context.Background()
-	_, _, err := testClient.ListBoards(ctx)
+	_, _, err := testClient.ListBoards(ctx, test.OrganizationIDs[0])
 
 	// Check for errors.
 	if err != nil {
@@ -147,15 +155,16 @@ func TestTrelloClient_GetBoards_RequestDetails(t *testing.T) {
 	}
 
 	// Check URL components.
-	expectedURL := "https://api.trello.com/1/organizations/organizationTest/boards?key=api-key&token=api-token"
+	expectedURL := "https://api.trello.com/1/organizations/organizationTest/boards"
 	if capturedRequest.URL.String() != expectedURL {
 		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
 	}
 
 	// Check headers.
 	expectedHeaders := map[string]string{
-		"Accept":       "application/json",
-		"Content-Type": "application/json",
+		"Accept":        "application/json",
+		"Content-Type":  "application/json",
+		"Authorization": `OAuth oauth_consumer_key="api-key", oauth_token="api-token"`,
 	}
 
 	for key, expectedValue := range expectedHeaders {
@@ -164,3 +173,975 @@ func TestTrelloClient_GetBoards_RequestDetails(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the client adds board members based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-members-idmember-put
+func TestTrelloClient_AddMemberToBoard_RequestDetails(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	var capturedBody []byte
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequest = req
+		capturedBody, _ = io.ReadAll(req.Body)
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Call AddMemberToBoard.
+	ctx := context.Background()
+	_, err := testClient.AddMemberToBoard(ctx, test.BoardIDs[0], test.UserIDs[0], "admin")
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest == nil {
+		t.Fatal("No request was captured")
+	}
+
+	if capturedRequest.Method != http.MethodPut {
+		t.Errorf("Expected method %s, got %s", http.MethodPut, capturedRequest.Method)
+	}
+
+	// Check URL components.
+	expectedURL := fmt.Sprintf("https://api.trello.com/1/boards/%s/members/%s", test.BoardIDs[0], test.UserIDs[0])
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+
+	// Check body.
+	expectedBody := `{"type":"admin"}`
+	if strings.TrimSpace(string(capturedBody)) != expectedBody {
+		t.Errorf("Expected body %s, got %s", expectedBody, string(capturedBody))
+	}
+}
+
+func TestTrelloClient_RemoveMemberFromBoard_RequestDetails(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequest = req
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Call RemoveMemberFromBoard.
+	ctx := context.Background()
+	_, err := testClient.RemoveMemberFromBoard(ctx, test.BoardIDs[0], test.UserIDs[0])
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest == nil {
+		t.Fatal("No request was captured")
+	}
+
+	if capturedRequest.Method != http.MethodDelete {
+		t.Errorf("Expected method %s, got %s", http.MethodDelete, capturedRequest.Method)
+	}
+
+	// Check URL components.
+	expectedURL := fmt.Sprintf("https://api.trello.com/1/boards/%s/members/%s", test.BoardIDs[0], test.UserIDs[0])
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+}
+
+// Tests that the client creates boards based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-post
+func TestTrelloClient_CreateBoard(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	var capturedBody []byte
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+				{
+					"id": "f7a6a858-ab65-4524-9632-b64a21aa3c79",
+					"name": "Finance",
+					"desc": "Finance team board",
+					"idOrganization": "organizationTest",
+					"prefs": {
+						"permissionLevel": "private"
+					}
+				}
+			`)),
+			Header: make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequest = req
+		capturedBody, _ = io.ReadAll(req.Body)
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Call CreateBoard.
+	ctx := context.Background()
+	board, _, err := testClient.CreateBoard(ctx, test.OrganizationIDs[0], "Finance", "Finance team board", "private")
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest.Method != http.MethodPost {
+		t.Errorf("Expected method %s, got %s", http.MethodPost, capturedRequest.Method)
+	}
+
+	expectedURL := "https://api.trello.com/1/boards"
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+
+	expectedBody := `{"name":"Finance","desc":"Finance team board","idOrganization":"organizationTest","prefs_permissionLevel":"private"}`
+	if strings.TrimSpace(string(capturedBody)) != expectedBody {
+		t.Errorf("Expected body %s, got %s", expectedBody, string(capturedBody))
+	}
+
+	// Verify the result.
+	if board == nil || board.ID != test.BoardIDs[0] || board.Preferences.PermissionLevel != "private" {
+		t.Errorf("Unexpected board: got %+v", board)
+	}
+}
+
+// Tests that deleting a board closes (archives) it by default, based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-put
+func TestTrelloClient_CloseBoard_RequestDetails(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	var capturedBody []byte
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequest = req
+		if req.Body != nil {
+			capturedBody, _ = io.ReadAll(req.Body)
+		}
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Delete the board.
+	ctx := context.Background()
+	boardResourceID := &v2.ResourceId{ResourceType: boardResourceType.Id, Resource: test.BoardIDs[0]}
+	_, err := newBoardBuilder(testClient, false, false, false, nil).Delete(ctx, boardResourceID)
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest == nil {
+		t.Fatal("No request was captured")
+	}
+
+	if capturedRequest.Method != http.MethodPut {
+		t.Errorf("Expected method %s, got %s", http.MethodPut, capturedRequest.Method)
+	}
+
+	// Check URL components.
+	expectedURL := fmt.Sprintf("https://api.trello.com/1/boards/%s", test.BoardIDs[0])
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+
+	// Check body.
+	expectedBody := `{"closed":true}`
+	if strings.TrimSpace(string(capturedBody)) != expectedBody {
+		t.Errorf("Expected body %s, got %s", expectedBody, string(capturedBody))
+	}
+}
+
+// Tests that deleting a board removes it permanently when boards are deleted, based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-delete
+func TestTrelloClient_DeleteBoard_RequestDetails(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	var capturedBody []byte
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequest = req
+		if req.Body != nil {
+			capturedBody, _ = io.ReadAll(req.Body)
+		}
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Delete the board.
+	ctx := context.Background()
+	boardResourceID := &v2.ResourceId{ResourceType: boardResourceType.Id, Resource: test.BoardIDs[0]}
+	_, err := newBoardBuilder(testClient, true, false, false, nil).Delete(ctx, boardResourceID)
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest == nil {
+		t.Fatal("No request was captured")
+	}
+
+	if capturedRequest.Method != http.MethodDelete {
+		t.Errorf("Expected method %s, got %s", http.MethodDelete, capturedRequest.Method)
+	}
+
+	// Check URL components.
+	expectedURL := fmt.Sprintf("https://api.trello.com/1/boards/%s", test.BoardIDs[0])
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+
+	// Check body.
+	expectedBody := ``
+	if strings.TrimSpace(string(capturedBody)) != expectedBody {
+		t.Errorf("Expected body %s, got %s", expectedBody, string(capturedBody))
+	}
+}
+
+// Tests that board memberships are fetched with the member details inline, based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-memberships-get
+func TestTrelloClient_ListMembershipsByBoard(t *testing.T) {
+	// Create a custom RoundTripper to capture the requests.
+	var capturedRequests []*http.Request
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+				[
+					{
+						"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2",
+						"idMember": "ea960e6c-f613-4bed-8852-ab012603915b",
+						"memberType": "admin",
+						"member": {
+							"id": "ea960e6c-f613-4bed-8852-ab012603915b",
+							"fullName": "Test User 1",
+							"username": "tester1"
+						}
+					},
+					{
+						"id": "5c0d1d4e-6a8b-4b36-8f0d-1f6f3f6b2e19",
+						"idMember": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f",
+						"memberType": "observer",
+						"deactivated": true,
+						"member": {
+							"id": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f",
+							"fullName": "Test User 2",
+							"username": "tester2"
+						}
+					}
+				]
+			`)),
+			Header: make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequests = append(capturedRequests, req)
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Call ListMembershipsByBoard.
+	ctx := context.Background()
+	result, err := testClient.ListMembershipsByBoard(ctx, test.BoardIDs[0])
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Members are inline, so no member lookups should happen.
+	if len(capturedRequests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(capturedRequests))
+	}
+
+	expectedURL := fmt.Sprintf("https://api.trello.com/1/boards/%s/memberships?member=true", test.BoardIDs[0])
+	if capturedRequests[0].URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequests[0].URL.String())
+	}
+
+	expectedUsers := []client.User{
+		{
+			ID:         test.UserIDs[0],
+			MemberID:   test.UserIDs[0],
+			Name:       "Test User 1",
+			Username:   "tester1",
+			MemberType: "admin",
+		},
+		{
+			ID:          test.UserIDs[1],
+			MemberID:    test.UserIDs[1],
+			Name:        "Test User 2",
+			Username:    "tester2",
+			MemberType:  "observer",
+			Deactivated: true,
+		},
+	}
+
+	if !reflect.DeepEqual(result, expectedUsers) {
+		t.Errorf("Unexpected users: got %+v, want %+v", result, expectedUsers)
+	}
+}
+
+// Tests that closed boards are skipped unless they are included, and that included closed boards are marked closed
+// and their grants labelled.
+func TestBoardBuilder_ClosedBoards(t *testing.T) {
+	boards := `[
+		{"id": "` + test.BoardIDs[0] + `", "name": "Test 1", "idOrganization": "organizationTest", "closed": false},
+		{"id": "` + test.BoardIDs[1] + `", "name": "Test 2", "idOrganization": "organizationTest", "closed": true}
+	]`
+	memberships := `[{"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2", "idMember": "` + test.UserIDs[0] + `", "memberType": "admin",
+		"member": {"id": "` + test.UserIDs[0] + `", "username": "tester1"}}]`
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		body := `[]`
+		switch req.URL.Path {
+		case "/1/organizations/organizationTest/boards":
+			body = boards
+		case fmt.Sprintf("/1/boards/%s/memberships", test.BoardIDs[1]):
+			body = memberships
+		}
+
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+	ctx := context.Background()
+	organizationResourceID := &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: test.OrganizationIDs[0]}
+
+	resources, _, _, err := newBoardBuilder(testClient, false, false, false, nil).List(ctx, organizationResourceID, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resources) != 1 || resources[0].Id.Resource != test.BoardIDs[0] {
+		t.Fatalf("Expected only the open board, got %v", resources)
+	}
+
+	builder := newBoardBuilder(testClient, false, true, false, nil)
+	resources, _, _, err = builder.List(ctx, organizationResourceID, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 boards, got %d", len(resources))
+	}
+
+	closedBoard := resources[1]
+	if !boardClosed(closedBoard) || boardClosed(resources[0]) {
+		t.Errorf("Expected only board %s to be marked closed", test.BoardIDs[1])
+	}
+
+	grants, _, _, err := builder.Grants(ctx, closedBoard, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(grants) != 1 {
+		t.Fatalf("Expected 1 grant, got %d", len(grants))
+	}
+
+	grantAnnotations := annotations.Annotations(grants[0].Annotations)
+	metadata := &v2.GrantMetadata{}
+	ok, err := grantAnnotations.Pick(metadata)
+	if err != nil || !ok {
+		t.Fatalf("Expected grant metadata, got %v", err)
+	}
+	if !metadata.GetMetadata().GetFields()["board_closed"].GetBoolValue() {
+		t.Errorf("Expected the grant to be labelled board_closed, got %v", metadata.GetMetadata())
+	}
+}
+
+// Tests that the board filter keeps the included boards, by ID or name pattern, and drops the excluded ones.
+func TestTrelloClient_ListBoards_Filter(t *testing.T) {
+	boards := `[
+		{"id": "` + test.BoardIDs[0] + `", "name": "Finance", "idOrganization": "organizationTest"},
+		{"id": "` + test.BoardIDs[1] + `", "name": "Scratch 42", "idOrganization": "organizationTest"},
+		{"id": "5f8d0d55b54764421b7156c1", "name": "HR Payroll", "idOrganization": "organizationTest"}
+	]`
+
+	tests := []struct {
+		name        string
+		include     []string
+		exclude     []string
+		expectedIDs []string
+	}{
+		{
+			name:        "no filter",
+			expectedIDs: []string{test.BoardIDs[0], test.BoardIDs[1], "5f8d0d55b54764421b7156c1"},
+		},
+		{
+			name:        "include by id and name",
+			include:     []string{test.BoardIDs[0], "^HR "},
+			expectedIDs: []string{test.BoardIDs[0], "5f8d0d55b54764421b7156c1"},
+		},
+		{
+			name:        "exclude by name",
+			exclude:     []string{"(?i)^scratch"},
+			expectedIDs: []string{test.BoardIDs[0], "5f8d0d55b54764421b7156c1"},
+		},
+		{
+			name:        "exclude wins over include",
+			include:     []string{"a"},
+			exclude:     []string{"5f8d0d55b54764421b7156c1"},
+			expectedIDs: []string{test.BoardIDs[0], test.BoardIDs[1]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockResponse := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(boards)),
+			}
+			mockResponse.Header.Set("Content-Type", "application/json")
+			testClient := test.NewTestClient(mockResponse, nil)
+
+			filter, err := client.NewBoardFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			testClient.BoardFilter = filter
+
+			result, _, err := testClient.ListBoards(context.Background(), test.OrganizationIDs[0])
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var ids []string
+			for _, board := range result {
+				ids = append(ids, board.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.expectedIDs) {
+				t.Errorf("Expected boards %v, got %v", tt.expectedIDs, ids)
+			}
+		})
+	}
+
+	if _, err := client.NewBoardFilter([]string{"("}, nil); err == nil {
+		t.Error("Expected an error for an invalid board pattern")
+	}
+}
+
+// Tests that personal boards are listed under the synced member that owns them, based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-boards-get
+func TestBoardBuilder_PersonalBoards(t *testing.T) {
+	const outsiderID = "5f8d0d55b54764421b7156c1"
+	memberBoards := `[
+		{"id": "64b7f1a0c0ffee0000000001", "name": "Own", "idOrganization": null, "idMemberCreator": "` + test.UserIDs[0] + `"},
+		{"id": "64b7f1a0c0ffee0000000002", "name": "Workspace", "idOrganization": "organizationTest", "idMemberCreator": "` + test.UserIDs[0] + `"},
+		{"id": "64b7f1a0c0ffee0000000003", "name": "Shared with tester2", "idOrganization": null, "idMemberCreator": "` + outsiderID + `",
+			"memberships": [{"idMember": "` + outsiderID + `"}, {"idMember": "` + test.UserIDs[1] + `"}, {"idMember": "` + test.UserIDs[0] + `"}]},
+		{"id": "64b7f1a0c0ffee0000000004", "name": "Shared with tester1", "idOrganization": null, "idMemberCreator": "` + outsiderID + `",
+			"memberships": [{"idMember": "` + outsiderID + `"}, {"idMember": "` + test.UserIDs[0] + `"}]}
+	]`
+
+	routes := map[string]string{
+		"/1/members/" + test.UserIDs[0] + "/boards":     memberBoards,
+		"/1/organizations/organizationTest/members":     test.ReadFile("usersMock.json"),
+		"/1/organizations/organizationTest/memberships": `[]`,
+		"/1/members/me": `{}`,
+	}
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		body, ok := routes[req.URL.Path]
+		if !ok {
+			t.Errorf("Unexpected request to %s", req.URL.Path)
+			body = `[]`
+		}
+
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+	ctx := context.Background()
+
+	users, _, _, err := newUserBuilder(testClient, true, nil).List(ctx, nil, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(users) == 0 {
+		t.Fatal("Expected users")
+	}
+
+	var childTypes []string
+	for _, annotation := range users[0].Annotations {
+		childType := &v2.ChildResourceType{}
+		if annotation.UnmarshalTo(childType) == nil {
+			childTypes = append(childTypes, childType.ResourceTypeId)
+		}
+	}
+	if !reflect.DeepEqual(childTypes, []string{tokenResourceType.Id, boardResourceType.Id}) {
+		t.Errorf("Expected token and board children, got %v", childTypes)
+	}
+
+	resources, _, _, err := newBoardBuilder(testClient, false, false, true, nil).List(ctx, users[0].Id, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ids []string
+	for _, boardResource := range resources {
+		ids = append(ids, boardResource.Id.Resource)
+		if boardResource.ParentResourceId.GetResource() != test.UserIDs[0] {
+			t.Errorf("Expected board %s under user %s, got %v", boardResource.Id.Resource, test.UserIDs[0], boardResource.ParentResourceId)
+		}
+	}
+
+	expectedIDs := []string{"64b7f1a0c0ffee0000000001", "64b7f1a0c0ffee0000000004"}
+	if !reflect.DeepEqual(ids, expectedIDs) {
+		t.Errorf("Expected personal boards %v, got %v", expectedIDs, ids)
+	}
+
+	// Without personal boards, users have no boards.
+	resources, _, _, err = newBoardBuilder(testClient, false, false, false, nil).List(ctx, users[0].Id, &pagination.Token{})
+	if err != nil || len(resources) != 0 {
+		t.Errorf("Expected no boards, got %v, %v", resources, err)
+	}
+}
+
+// Tests that organizations advertise boards as children, and that boards are only listed for the parent organization.
+func TestBoardBuilder_List_Parent(t *testing.T) {
+	organizationResource, err := parseIntoOrganizationResource(context.Background(), &client.Organization{ID: "1ed53893-6225-4d74-9806-3eedcbb402dd", DisplayName: "Test"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	organizationAnnotations := annotations.Annotations(organizationResource.Annotations)
+	childType := &v2.ChildResourceType{}
+	if ok, err := organizationAnnotations.Pick(childType); err != nil || !ok || childType.ResourceTypeId != boardResourceType.Id {
+		t.Errorf("Expected organizations to have board children, got %v", childType)
+	}
+
+	var capturedRequests []*http.Request
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		capturedRequests = append(capturedRequests, req)
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`[{"id": "` + test.BoardIDs[0] + `", "name": "Test 1", "idOrganization": "1ed53893-6225-4d74-9806-3eedcbb402dd"}]`)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", []string{"organizationTest", "otherOrganization"}, uhttp.NewBaseHttpClient(httpClient))
+	builder := newBoardBuilder(testClient, false, false, false, nil)
+	ctx := context.Background()
+
+	// Boards have no top level listing.
+	resources, nextPageToken, _, err := builder.List(ctx, nil, &pagination.Token{})
+	if err != nil || len(resources) != 0 || nextPageToken != "" {
+		t.Errorf("Expected no boards without a parent, got %v, %q, %v", resources, nextPageToken, err)
+	}
+
+	resources, nextPageToken, _, err = builder.List(ctx, organizationResource.Id, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if nextPageToken != "" {
+		t.Errorf("Expected a single page, got next page token %q", nextPageToken)
+	}
+
+	if len(capturedRequests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(capturedRequests))
+	}
+	expectedURL := "https://api.trello.com/1/organizations/1ed53893-6225-4d74-9806-3eedcbb402dd/boards"
+	if capturedRequests[0].URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequests[0].URL.String())
+	}
+
+	if len(resources) != 1 || resources[0].ParentResourceId.GetResource() != organizationResource.Id.Resource {
+		t.Errorf("Expected 1 board under the organization, got %v", resources)
+	}
+}
+
+// Tests that boards visible to the organization or the public have a viewer entitlement granted to the organization,
+// expanding to the organization's members, while private boards don't.
+func TestBoardBuilder_Viewer(t *testing.T) {
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`[]`)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+	builder := newBoardBuilder(testClient, false, false, false, nil)
+	ctx := context.Background()
+	organizationResourceID := &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: test.OrganizationIDs[0]}
+
+	tests := []struct {
+		permissionLevel string
+		viewer          bool
+	}{
+		{permissionLevel: "org", viewer: true},
+		{permissionLevel: "public", viewer: true},
+		{permissionLevel: "private", viewer: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permissionLevel, func(t *testing.T) {
+			board := &client.Board{ID: test.BoardIDs[0], Name: "Test 1", Preferences: client.Preferences{PermissionLevel: tt.permissionLevel}}
+			boardResource, err := parseIntoBoardResource(ctx, board, organizationResourceID)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			entitlements, _, _, err := builder.Entitlements(ctx, boardResource, &pagination.Token{})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			viewerEntitlementID := "board:" + test.BoardIDs[0] + ":viewer"
+			hasViewer := false
+			for _, boardEntitlement := range entitlements {
+				if boardEntitlement.Id == viewerEntitlementID {
+					hasViewer = true
+				}
+			}
+			if hasViewer != tt.viewer {
+				t.Errorf("Expected viewer entitlement %v, got %v", tt.viewer, hasViewer)
+			}
+
+			grants, _, _, err := builder.Grants(ctx, boardResource, &pagination.Token{})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if !tt.viewer {
+				if len(grants) != 0 {
+					t.Errorf("Expected no grants, got %d", len(grants))
+				}
+				return
+			}
+
+			if len(grants) != 1 {
+				t.Fatalf("Expected 1 grant, got %d", len(grants))
+			}
+			viewerGrant := grants[0]
+			if viewerGrant.Entitlement.Id != viewerEntitlementID || viewerGrant.Principal.Id.Resource != test.OrganizationIDs[0] {
+				t.Errorf("Expected the viewer grant to the organization, got %v", viewerGrant)
+			}
+
+			grantAnnotations := annotations.Annotations(viewerGrant.Annotations)
+			expandable := &v2.GrantExpandable{}
+			if ok, err := grantAnnotations.Pick(expandable); err != nil || !ok {
+				t.Fatalf("Expected the viewer grant to be expandable, got %v", err)
+			}
+
+			expectedEntitlementIDs := []string{"organization:organizationTest:active"}
+			if !reflect.DeepEqual(expandable.EntitlementIds, expectedEntitlementIDs) {
+				t.Errorf("Expected expansion from %v, got %v", expectedEntitlementIDs, expandable.EntitlementIds)
+			}
+		})
+	}
+}
+
+// Tests that boards have one entitlement per member type, and that each membership is granted the entitlement of
+// its member type.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-memberships-get
+func TestBoardBuilder_EntitlementsAndGrants(t *testing.T) {
+	memberships := `[
+		{"id": "membership-1", "idMember": "` + test.UserIDs[0] + `", "memberType": "admin", "member": {"id": "` + test.UserIDs[0] + `", "username": "tester1"}},
+		{"id": "membership-2", "idMember": "` + test.UserIDs[1] + `", "memberType": "observer", "member": {"id": "` + test.UserIDs[1] + `", "username": "tester2"}}
+	]`
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(memberships)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+	builder := newBoardBuilder(testClient, false, false, false, nil)
+	ctx := context.Background()
+
+	organizationResourceID := &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: test.OrganizationIDs[0]}
+	board := &client.Board{ID: test.BoardIDs[0], Name: "Test 1", Preferences: client.Preferences{PermissionLevel: "private"}}
+	boardResource, err := parseIntoBoardResource(ctx, board, organizationResourceID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entitlements, _, _, err := builder.Entitlements(ctx, boardResource, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var entitlementIDs []string
+	for _, boardEntitlement := range entitlements {
+		entitlementIDs = append(entitlementIDs, boardEntitlement.Id)
+	}
+	expectedEntitlementIDs := []string{
+		"board:" + test.BoardIDs[0] + ":admin",
+		"board:" + test.BoardIDs[0] + ":normal",
+		"board:" + test.BoardIDs[0] + ":observer",
+	}
+	if !reflect.DeepEqual(entitlementIDs, expectedEntitlementIDs) {
+		t.Errorf("Expected entitlements %v, got %v", expectedEntitlementIDs, entitlementIDs)
+	}
+
+	grants, _, _, err := builder.Grants(ctx, boardResource, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	grantedEntitlements := map[string]string{}
+	for _, boardGrant := range grants {
+		grantedEntitlements[boardGrant.Principal.Id.Resource] = boardGrant.Entitlement.Id
+	}
+	expectedGrantedEntitlements := map[string]string{
+		test.UserIDs[0]: "board:" + test.BoardIDs[0] + ":admin",
+		test.UserIDs[1]: "board:" + test.BoardIDs[0] + ":observer",
+	}
+	if len(grants) != len(expectedGrantedEntitlements) || !reflect.DeepEqual(grantedEntitlements, expectedGrantedEntitlements) {
+		t.Errorf("Expected grants %v, got %v", expectedGrantedEntitlements, grantedEntitlements)
+	}
+}
+
+// grantStep is a grant or revoke made through a builder, along with its expected outcome.
+type grantStep struct {
+	revoke             bool
+	principal          *v2.ResourceId
+	memberType         string
+	expectedError      bool
+	expectedAnnotation proto.Message
+}
+
+// runGrantSteps makes the grants and revokes of the steps in order through the same builder, so each step sees the
+// changes made by the previous ones.
+func runGrantSteps(t *testing.T, builder connectorbuilder.ResourceProvisionerV2, resource *v2.Resource, steps []grantStep) {
+	t.Helper()
+	ctx := context.Background()
+
+	for index, step := range steps {
+		var (
+			grants []*v2.Grant
+			annos  annotations.Annotations
+			err    error
+		)
+		if step.revoke {
+			annos, err = builder.Revoke(ctx, grant.NewGrant(resource, step.memberType, step.principal))
+		} else {
+			grants, annos, err = builder.Grant(ctx, &v2.Resource{Id: step.principal}, entitlement.NewPermissionEntitlement(resource, step.memberType))
+		}
+
+		if step.expectedError {
+			if err == nil {
+				t.Errorf("Expected an error in step %d", index)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected no error in step %d, got %v", index, err)
+		}
+
+		if step.expectedAnnotation != nil && !annos.Contains(step.expectedAnnotation) {
+			t.Errorf("Expected %T in step %d", step.expectedAnnotation, index)
+		}
+		if step.expectedAnnotation == nil && (annos.Contains(&v2.GrantAlreadyExists{}) || annos.Contains(&v2.GrantAlreadyRevoked{})) {
+			t.Errorf("Expected the membership to change in step %d, got %v", index, annos)
+		}
+
+		expectedEntitlementID := entitlement.NewEntitlementID(resource, step.memberType)
+		if !step.revoke && (len(grants) != 1 || grants[0].Entitlement.Id != expectedEntitlementID) {
+			t.Errorf("Expected a grant of %s in step %d, got %v", expectedEntitlementID, index, grants)
+		}
+	}
+}
+
+// Tests that board grants and revokes change memberships, see the changes made right before them, reject principals
+// that aren't users, and report memberships that already match instead of changing them, based on the documented
+// APIs below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-memberships-get
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-members-idmember-put
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-id-members-idmember-delete
+func TestBoardBuilder_GrantRevoke(t *testing.T) {
+	boardPath := "/boards/" + test.BoardIDs[0]
+	memberPath := boardPath + "/members/" + test.UserIDs[0]
+	userID := &v2.ResourceId{ResourceType: userResourceType.Id, Resource: test.UserIDs[0]}
+	organizationID := &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: test.OrganizationIDs[0]}
+
+	tests := []struct {
+		name               string
+		memberType         string
+		steps              []grantStep
+		expectedMemberType string
+		expectedRequests   []string
+	}{
+		{
+			name: "grant then revoke",
+			steps: []grantStep{
+				{principal: userID, memberType: "observer"},
+				{revoke: true, principal: userID, memberType: "observer"},
+			},
+			expectedRequests: []string{http.MethodPut + " " + memberPath, http.MethodDelete + " " + memberPath},
+		},
+		{
+			name:               "grant to non-user",
+			memberType:         "normal",
+			steps:              []grantStep{{principal: organizationID, memberType: "normal", expectedError: true}},
+			expectedMemberType: "normal",
+		},
+		{
+			name:               "revoke from non-user",
+			memberType:         "normal",
+			steps:              []grantStep{{revoke: true, principal: organizationID, memberType: "normal", expectedError: true}},
+			expectedMemberType: "normal",
+		},
+		{
+			name:               "grant already exists",
+			memberType:         "normal",
+			steps:              []grantStep{{principal: userID, memberType: "normal", expectedAnnotation: &v2.GrantAlreadyExists{}}},
+			expectedMemberType: "normal",
+		},
+		{
+			name:               "grant already revoked",
+			memberType:         "normal",
+			steps:              []grantStep{{revoke: true, principal: userID, memberType: "admin", expectedAnnotation: &v2.GrantAlreadyRevoked{}}},
+			expectedMemberType: "normal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberships := map[string]*test.Membership{}
+			if tt.memberType != "" {
+				memberships[test.UserIDs[0]] = &test.Membership{ID: "membership-1", MemberID: test.UserIDs[0], MemberType: tt.memberType}
+			}
+			server := test.NewMembershipServer(map[string]map[string]*test.Membership{boardPath: memberships})
+			defer server.Close()
+
+			boardResource, err := parseIntoBoardResource(context.Background(), &client.Board{ID: test.BoardIDs[0], Name: "Test 1"}, nil)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			runGrantSteps(t, newBoardBuilder(server.Client(), false, false, false, nil), boardResource, tt.steps)
+
+			membership := server.Membership(boardPath, test.UserIDs[0])
+			if tt.expectedMemberType == "" && membership != nil {
+				t.Errorf("Expected no membership, got %+v", membership)
+			}
+			if tt.expectedMemberType != "" && (membership == nil || membership.MemberType != tt.expectedMemberType) {
+				t.Errorf("Expected a %s membership, got %+v", tt.expectedMemberType, membership)
+			}
+
+			if requests := server.Requests(); !reflect.DeepEqual(requests, tt.expectedRequests) {
+				t.Errorf("Expected requests %v, got %v", tt.expectedRequests, requests)
+			}
+		})
+	}
+}
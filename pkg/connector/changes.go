@@ -0,0 +1,123 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types"
+	"github.com/conductorone/baton-trello/pkg/webhook"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// syncScope limits a sync to the boards, organizations and members recorded in the webhook change log. The changes
+// are read once when the connector starts, and removed from the change log once the sync completes. A nil scope, or
+// a change log without changes, syncs everything.
+type syncScope struct {
+	changeLog     *webhook.ChangeLog
+	changes       webhook.Changes
+	boards        map[string]bool
+	organizations map[string]bool
+	members       map[string]bool
+}
+
+func newSyncScope(changesFile string) (*syncScope, error) {
+	if changesFile == "" {
+		return nil, nil
+	}
+
+	changeLog, err := webhook.OpenChangeLog(changesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := changeLog.Changes()
+
+	return &syncScope{
+		changeLog:     changeLog,
+		changes:       changes,
+		boards:        idSet(changes.Boards),
+		organizations: idSet(changes.Organizations),
+		members:       idSet(changes.Members),
+	}, nil
+}
+
+// limited reports whether the sync is limited to the recorded changes.
+func (s *syncScope) limited() bool {
+	return s != nil && !s.changes.Empty()
+}
+
+// board reports whether the board is synced.
+func (s *syncScope) board(boardID string) bool {
+	return !s.limited() || s.boards[boardID]
+}
+
+// organization reports whether the memberships of the organization are synced.
+func (s *syncScope) organization(organizationID string) bool {
+	return !s.limited() || s.organizations[organizationID]
+}
+
+// member reports whether the member is synced.
+func (s *syncScope) member(memberID string) bool {
+	return !s.limited() || s.members[memberID]
+}
+
+// clear removes the changes the sync was limited to from the change log.
+func (s *syncScope) clear() error {
+	if !s.limited() {
+		return nil
+	}
+
+	if err := s.changeLog.Remove(s.changes); err != nil {
+		return fmt.Errorf("trello-connector: failed to clear synced changes: %w", err)
+	}
+
+	return nil
+}
+
+func idSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+
+	return set
+}
+
+// changeLogServer clears the synced changes from the change log once a sync completes. The SDK only calls Cleanup
+// after a sync succeeds, so the changes of a failed sync are kept for the next one.
+type changeLogServer struct {
+	types.ConnectorServer
+	scope *syncScope
+}
+
+func (s *changeLogServer) Cleanup(ctx context.Context, request *v2.ConnectorServiceCleanupRequest) (*v2.ConnectorServiceCleanupResponse, error) {
+	resp, err := s.ConnectorServer.Cleanup(ctx, request)
+	if err != nil || !s.scope.limited() {
+		return resp, err
+	}
+
+	if err := s.scope.clear(); err != nil {
+		return nil, err
+	}
+
+	ctxzap.Extract(ctx).Debug(
+		"trello-connector: cleared synced changes",
+		zap.Int("boards", len(s.scope.changes.Boards)),
+		zap.Int("organizations", len(s.scope.changes.Organizations)),
+		zap.Int("members", len(s.scope.changes.Members)),
+	)
+
+	return resp, nil
+}
+
+// WithChangeLogCleanup wraps the connector server so the changes a sync was limited to are removed from the change
+// log once the sync completes. The server is returned as is when no change log is configured.
+func (d *Connector) WithChangeLogCleanup(server types.ConnectorServer) types.ConnectorServer {
+	if d.scope == nil {
+		return server
+	}
+
+	return &changeLogServer{ConnectorServer: server, scope: d.scope}
+}
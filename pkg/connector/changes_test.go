@@ -0,0 +1,172 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/conductorone/baton-trello/pkg/webhook"
+	"github.com/conductorone/baton-trello/test"
+)
+
+// cleanupServer stands in for the connector server the SDK builds, counting the Cleanup calls it receives.
+type cleanupServer struct {
+	types.ConnectorServer
+	cleanups int
+}
+
+func (s *cleanupServer) Cleanup(_ context.Context, _ *v2.ConnectorServiceCleanupRequest) (*v2.ConnectorServiceCleanupResponse, error) {
+	s.cleanups++
+	return &v2.ConnectorServiceCleanupResponse{}, nil
+}
+
+// Tests that a sync given a change log only lists the recorded boards and members and the grants of the recorded
+// boards and organizations, and that the recorded changes are cleared once the sync completes.
+func TestSyncScope(t *testing.T) {
+	const otherOrganizationID = "otherOrganization"
+
+	path := filepath.Join(t.TempDir(), "changes.json")
+	changeLog, err := webhook.OpenChangeLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	err = changeLog.Record(&client.Action{
+		Type: "addMemberToBoard",
+		Data: client.ActionData{
+			Board:        &client.ActionModel{ID: test.BoardIDs[0]},
+			Organization: &client.ActionModel{ID: test.OrganizationIDs[0]},
+			IdMember:     test.UserIDs[0],
+		},
+		Member: &client.User{ID: test.UserIDs[0]},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	boards := `[
+		{"id": "` + test.BoardIDs[0] + `", "name": "Test 1", "idOrganization": "organizationTest"},
+		{"id": "` + test.BoardIDs[1] + `", "name": "Test 2", "idOrganization": "organizationTest"}
+	]`
+	members := `[
+		{"id": "` + test.UserIDs[0] + `", "username": "tester1"},
+		{"id": "` + test.UserIDs[1] + `", "username": "tester2"}
+	]`
+	memberships := `[{"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2", "idMember": "` + test.UserIDs[0] + `", "memberType": "admin",
+		"member": {"id": "` + test.UserIDs[0] + `", "username": "tester1"}}]`
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		body := `[]`
+		switch {
+		case req.URL.Path == "/1/organizations/organizationTest/boards":
+			body = boards
+		case req.URL.Path == "/1/organizations/organizationTest/members":
+			body = members
+		case strings.HasSuffix(req.URL.Path, "/memberships"):
+			body = memberships
+		}
+
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+	ctx := context.Background()
+
+	scope, err := newSyncScope(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !scope.limited() {
+		t.Fatalf("Expected the sync to be limited to the recorded changes")
+	}
+
+	users, _, _, err := newUserBuilder(testClient, false, scope).List(ctx, nil, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(users) != 1 || users[0].Id.Resource != test.UserIDs[0] {
+		t.Errorf("Expected only user %s, got %v", test.UserIDs[0], users)
+	}
+
+	boardBuilder := newBoardBuilder(testClient, false, false, false, scope)
+	organizationResourceID := &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: test.OrganizationIDs[0]}
+	boardResources, _, _, err := boardBuilder.List(ctx, organizationResourceID, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(boardResources) != 1 || boardResources[0].Id.Resource != test.BoardIDs[0] {
+		t.Fatalf("Expected only board %s, got %v", test.BoardIDs[0], boardResources)
+	}
+
+	for boardID, expected := range map[string]bool{test.BoardIDs[0]: true, test.BoardIDs[1]: false} {
+		boardResource := &v2.Resource{Id: &v2.ResourceId{ResourceType: boardResourceType.Id, Resource: boardID}}
+		grants, _, _, err := boardBuilder.Grants(ctx, boardResource, &pagination.Token{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if (len(grants) > 0) != expected {
+			t.Errorf("Expected grants on board %s: %t, got %d", boardID, expected, len(grants))
+		}
+	}
+
+	organizationBuilder := newOrganizationBuilder(testClient, scope)
+	for organizationID, expected := range map[string]bool{test.OrganizationIDs[0]: true, otherOrganizationID: false} {
+		organizationResource := &v2.Resource{Id: &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: organizationID}}
+		grants, _, _, err := organizationBuilder.Grants(ctx, organizationResource, &pagination.Token{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if (len(grants) > 0) != expected {
+			t.Errorf("Expected grants on organization %s: %t, got %d", organizationID, expected, len(grants))
+		}
+	}
+
+	server := &cleanupServer{}
+	connector := &Connector{client: testClient, scope: scope}
+	_, err = connector.WithChangeLogCleanup(server).Cleanup(ctx, &v2.ConnectorServiceCleanupRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if server.cleanups != 1 {
+		t.Errorf("Expected the connector server to be cleaned up once, got %d", server.cleanups)
+	}
+
+	reopened, err := webhook.OpenChangeLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if changes := reopened.Changes(); !changes.Empty() {
+		t.Errorf("Expected the synced changes to be cleared, got %v", changes)
+	}
+}
+
+// Tests that an empty change log doesn't limit the sync, and that the connector server isn't wrapped without one.
+func TestSyncScope_Empty(t *testing.T) {
+	scope, err := newSyncScope(filepath.Join(t.TempDir(), "changes.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if scope.limited() || !scope.board(test.BoardIDs[0]) || !scope.member(test.UserIDs[0]) {
+		t.Errorf("Expected an empty change log to sync everything")
+	}
+
+	server := &cleanupServer{}
+	if wrapped := (&Connector{}).WithChangeLogCleanup(server); wrapped != server {
+		t.Errorf("Expected the connector server to be returned as is without a change log")
+	}
+}
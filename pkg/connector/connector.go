@@ -2,8 +2,9 @@ package connector
 
 import (
 	"context"
-
+	"fmt"
 	"io"
+	"time"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -14,22 +15,28 @@ import (
 )
 
 type Connector struct {
-	client *client.TrelloClient
+	client              *client.TrelloClient
+	deleteBoards        bool
+	includeClosedBoards bool
+	personalBoards      bool
+	scope               *syncScope
 }
 
 // ResourceSyncers returns a ResourceSyncer for each resource type that should be synced from the upstream service.
 func (d *Connector) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
 	return []connectorbuilder.ResourceSyncer{
-		newUserBuilder(d.client),
-		newOrganizationBuilder(d.client),
-		newBoardBuilder(d.client),
+		newUserBuilder(d.client, d.personalBoards, d.scope),
+		newOrganizationBuilder(d.client, d.scope),
+		newBoardBuilder(d.client, d.deleteBoards, d.includeClosedBoards, d.personalBoards, d.scope),
+		newEnterpriseBuilder(d.client),
+		newTokenBuilder(d.client),
 	}
 }
 
 // Asset takes an input AssetRef and attempts to fetch it using the connector's authenticated http client
 // It streams a response, always starting with a metadata object, following by chunked payloads for the asset.
-func (d *Connector) Asset(_ context.Context, _ *v2.AssetRef) (string, io.ReadCloser, error) {
-	return "", nil, nil
+func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.ReadCloser, error) {
+	return d.client.GetAsset(ctx, asset.GetId())
 }
 
 // Metadata returns metadata about the connector.
@@ -37,17 +44,97 @@ func (d *Connector) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error)
 	return &v2.ConnectorMetadata{
 		DisplayName: "Trello Connector",
 		Description: "Connector to sync users, organizations and boards from Trello",
+		AccountCreationSchema: &v2.ConnectorAccountCreationSchema{
+			FieldMap: map[string]*v2.ConnectorAccountCreationSchema_Field{
+				"email": {
+					DisplayName: "Email",
+					Required:    true,
+					Description: "The email address the Trello invitation is sent to.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "Email",
+					Order:       1,
+				},
+				"full_name": {
+					DisplayName: "Full name",
+					Required:    true,
+					Description: "The full name of the invited member.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "Full name",
+					Order:       2,
+				},
+				"organization": {
+					DisplayName: "Organization",
+					Required:    false,
+					Description: "The ID or slug of the organization to invite the member into. Defaults to the first configured organization.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "Organization",
+					Order:       3,
+				},
+			},
+		},
 	}, nil
 }
 
-// Validate is called to ensure that the connector is properly configured. It should exercise any API credentials
-// to be sure that they are valid.
-func (d *Connector) Validate(_ context.Context) (annotations.Annotations, error) {
+// Validate is called to ensure that the connector is properly configured. It checks that the API key and token
+// authenticate a member, that the token hasn't expired and can read organizations, and that every organization to
+// sync resolves.
+func (d *Connector) Validate(ctx context.Context) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	member, _, err := d.client.GetMemberDetails(ctx, "me")
+	if err != nil {
+		return nil, fmt.Errorf("trello-connector: failed to authenticate, check that the API key and token are valid and belong together: %w", err)
+	}
+
+	token, _, err := d.client.GetToken(ctx, d.client.ApiToken)
+	if err != nil {
+		return nil, fmt.Errorf("trello-connector: failed to get the API token metadata, check that the token is valid: %w", err)
+	}
+
+	if token.Expired(time.Now()) {
+		return nil, fmt.Errorf("trello-connector: the API token expired on %s, generate a new token", token.DateExpires.Format(time.RFC3339))
+	}
+
+	if !token.CanRead("Organization") {
+		return nil, fmt.Errorf("trello-connector: the API token can't read organizations, generate a token with the read scope")
+	}
+
+	organizationIDs, err := d.client.ListOrganizationIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("trello-connector: failed to list the organizations to sync: %w", err)
+	}
+
+	if len(organizationIDs) == 0 {
+		return nil, fmt.Errorf("trello-connector: no organizations to sync, member %s doesn't belong to any organization that isn't excluded", member.Username)
+	}
+
+	for _, organizationID := range organizationIDs {
+		_, _, err := d.client.GetOrganizationDetail(ctx, organizationID)
+		if err != nil {
+			return nil, fmt.Errorf("trello-connector: organization %s could not be resolved, check that the organization ID or slug is correct and that member %s can see it: %w", organizationID, member.Username, err)
+		}
+	}
+
+	l.Debug(
+		"trello-connector: validated credentials",
+		zap.String("member_id", member.ID),
+		zap.Strings("organization_ids", organizationIDs),
+	)
+
 	return nil, nil
 }
 
-// New returns a new instance of the connector.
-func New(ctx context.Context, trelloClient *client.TrelloClient) (*Connector, error) {
+// New returns a new instance of the connector. When deleteBoards is set, deleting a board removes it permanently
+// instead of closing it. Closed boards are only synced when includeClosedBoards is set, and boards outside any
+// organization are only synced, under the members that own them, when personalBoards is set. When changesFile names
+// a webhook change log holding changes, syncs are limited to the boards, organizations and members it records.
+func New(ctx context.Context, trelloClient *client.TrelloClient, deleteBoards, includeClosedBoards, personalBoards bool, changesFile string) (*Connector, error) {
 	l := ctxzap.Extract(ctx)
 
 	trelloClient, err := client.New(ctx, trelloClient)
@@ -56,7 +143,17 @@ func New(ctx context.Context, trelloClient *client.TrelloClient) (*Connector, er
 		return nil, err
 	}
 
+	scope, err := newSyncScope(changesFile)
+	if err != nil {
+		l.Error("error reading the change log", zap.Error(err))
+		return nil, err
+	}
+
 	return &Connector{
-		client: trelloClient,
+		client:              trelloClient,
+		deleteBoards:        deleteBoards,
+		includeClosedBoards: includeClosedBoards,
+		personalBoards:      personalBoards,
+		scope:               scope,
 	}, nil
 }
@@ -0,0 +1,178 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/conductorone/baton-trello/test"
+)
+
+// newValidateTestClient returns a client that answers the members, tokens and organizations endpoints used by
+// Validate with the given bodies, keyed by the first path segment after the API version.
+func newValidateTestClient(t *testing.T, bodies map[string]string) *client.TrelloClient {
+	t.Helper()
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		segments := strings.Split(strings.TrimPrefix(req.URL.Path, "/1/"), "/")
+		body, ok := bodies[segments[0]]
+		if !ok {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(`"model not found"`)),
+			}, nil
+		}
+
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	return client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+}
+
+// Tests that Validate checks the credentials, the token metadata and the organizations, based on the documented
+// APIs below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-get
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-get
+func TestConnector_Validate(t *testing.T) {
+	member := `{"id": "ea960e6c-f613-4bed-8852-ab012603915b", "username": "tester1"}`
+	organization := `{"id": "1ed53893-6225-4d74-9806-3eedcbb402dd", "name": "organizationTest"}`
+
+	tests := []struct {
+		name          string
+		bodies        map[string]string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			bodies: map[string]string{
+				"members":       member,
+				"tokens":        `{"id": "token-id", "dateExpires": null, "permissions": [{"idModel": "*", "modelType": "Organization", "read": true, "write": true}]}`,
+				"organizations": organization,
+			},
+		},
+		{
+			name: "invalid credentials",
+			bodies: map[string]string{
+				"organizations": organization,
+			},
+			expectedError: "check that the API key and token are valid",
+		},
+		{
+			name: "expired token",
+			bodies: map[string]string{
+				"members":       member,
+				"tokens":        `{"id": "token-id", "dateExpires": "2020-01-01T00:00:00.000Z", "permissions": [{"idModel": "*", "modelType": "Organization", "read": true, "write": true}]}`,
+				"organizations": organization,
+			},
+			expectedError: "the API token expired on 2020-01-01T00:00:00Z",
+		},
+		{
+			name: "token without organization access",
+			bodies: map[string]string{
+				"members":       member,
+				"tokens":        `{"id": "token-id", "dateExpires": null, "permissions": [{"idModel": "*", "modelType": "Board", "read": true, "write": true}]}`,
+				"organizations": organization,
+			},
+			expectedError: "can't read organizations",
+		},
+		{
+			name: "unknown organization",
+			bodies: map[string]string{
+				"members": member,
+				"tokens":  `{"id": "token-id", "dateExpires": null, "permissions": [{"idModel": "*", "modelType": "Organization", "read": true, "write": false}]}`,
+			},
+			expectedError: "organization organizationTest could not be resolved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &Connector{client: newValidateTestClient(t, tt.bodies)}
+
+			_, err := connector.Validate(context.Background())
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+// Tests that Asset streams member avatars from Trello with their content type, and refuses URLs outside Trello.
+// https://developer.atlassian.com/cloud/trello/guides/rest-api/object-definitions/#member-object
+func TestConnector_Asset(t *testing.T) {
+	const avatarURL = "https://trello-members.s3.amazonaws.com/ea960e6c-f613-4bed-8852-ab012603915b/4f6b0ae8a1ee0c6e/170.png"
+	image := "\x89PNG\r\n\x1a\n"
+
+	var capturedRequests []*http.Request
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		capturedRequests = append(capturedRequests, req)
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(image)),
+		}
+		response.Header.Set("Content-Type", "image/png")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+	connector := &Connector{client: testClient}
+
+	contentType, body, err := connector.Asset(context.Background(), &v2.AssetRef{Id: avatarURL})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer body.Close()
+
+	if contentType != "image/png" {
+		t.Errorf("Expected content type image/png, got %s", contentType)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != image {
+		t.Errorf("Expected the avatar bytes, got %q", data)
+	}
+
+	if len(capturedRequests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(capturedRequests))
+	}
+	// The credentials are only for the API, so they must not be sent to the image host.
+	if capturedRequests[0].URL.String() != avatarURL {
+		t.Errorf("Expected URL %s, got %s", avatarURL, capturedRequests[0].URL.String())
+	}
+
+	_, _, err = connector.Asset(context.Background(), &v2.AssetRef{Id: "https://example.com/avatar.png"})
+	if err == nil {
+		t.Error("Expected an error for an asset outside Trello")
+	}
+	if len(capturedRequests) != 1 {
+		t.Errorf("Expected no request for an asset outside Trello, got %d requests", len(capturedRequests))
+	}
+}
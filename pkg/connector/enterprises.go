@@ -0,0 +1,136 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-trello/pkg/client"
+)
+
+const (
+	enterpriseAdmin  = "admin"
+	enterpriseMember = "member"
+)
+
+type enterpriseBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.TrelloClient
+}
+
+func (o *enterpriseBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return enterpriseResourceType
+}
+
+// List returns the configured enterprise. Nothing is returned when no enterprise is configured.
+func (o *enterpriseBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID != nil || o.client.EnterpriseID == "" {
+		return nil, "", nil, nil
+	}
+
+	enterprise, annotation, err := o.client.GetEnterprise(ctx, o.client.EnterpriseID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	enterpriseResource, err := parseIntoEnterpriseResource(ctx, enterprise)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return []*v2.Resource{enterpriseResource}, "", annotation, nil
+}
+
+func parseIntoEnterpriseResource(_ context.Context, enterprise *client.Enterprise) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"enterprise_id":      enterprise.ID,
+		"name":               enterprise.Name,
+		"display_name":       enterprise.DisplayName,
+		"organization_count": len(enterprise.IdOrganizations),
+	}
+
+	groupTraits := []resource.GroupTraitOption{
+		resource.WithGroupProfile(profile),
+	}
+
+	displayName := enterprise.DisplayName
+
+	ret, err := resource.NewGroupResource(
+		displayName,
+		enterpriseResourceType,
+		enterprise.ID,
+		groupTraits,
+		resource.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: organizationResourceType.Id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *enterpriseBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var entitlements []*v2.Entitlement
+
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Admin of enterprise %s in Trello", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Enterprise admin", resource.DisplayName)),
+	}
+	entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, enterpriseAdmin, assigmentOptions...))
+
+	assigmentOptions = []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Licensed member managed by enterprise %s in Trello", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Enterprise member", resource.DisplayName)),
+	}
+	entitlements = append(entitlements, entitlement.NewAssignmentEntitlement(resource, enterpriseMember, assigmentOptions...))
+
+	return entitlements, "", nil, nil
+}
+
+func (o *enterpriseBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	var enterpriseID = resource.Id.Resource
+
+	admins, _, err := o.client.ListEnterpriseAdmins(ctx, enterpriseID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, admin := range admins {
+		userResource, _ := parseIntoUserResource(ctx, &admin, resource.Id)
+		adminGrant := grant.NewGrant(resource, enterpriseAdmin, userResource, grant.WithAnnotation(&v2.V1Identifier{
+			Id: fmt.Sprintf("enterprise-grant:%s:%s:%s", enterpriseID, admin.ID, enterpriseAdmin),
+		}))
+		grants = append(grants, adminGrant)
+	}
+
+	members, annotation, err := o.client.ListEnterpriseMembers(ctx, enterpriseID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, member := range members {
+		userResource, _ := parseIntoUserResource(ctx, &member, resource.Id)
+		memberGrant := grant.NewGrant(resource, enterpriseMember, userResource, grant.WithAnnotation(&v2.V1Identifier{
+			Id: fmt.Sprintf("enterprise-grant:%s:%s:%s", enterpriseID, member.ID, enterpriseMember),
+		}))
+		grants = append(grants, memberGrant)
+	}
+
+	return grants, "", annotation, nil
+}
+
+func newEnterpriseBuilder(c *client.TrelloClient) *enterpriseBuilder {
+	return &enterpriseBuilder{
+		resourceType: enterpriseResourceType,
+		client:       c,
+	}
+}
@@ -0,0 +1,170 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/conductorone/baton-trello/test"
+)
+
+// Tests that the client can fetch enterprises based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-enterprises/#api-enterprises-id-get
+func TestTrelloClient_GetEnterprise(t *testing.T) {
+	// Create a mock response.
+	mockResponse := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body: io.NopCloser(strings.NewReader(`
+			{
+				"id": "5e2b6b0f-3e7c-4bd6-9c52-0cf8b2fd1c4e",
+				"name": "enterpriseTest",
+				"displayName": "Trello Enterprise Test",
+				"idAdmins": ["ea960e6c-f613-4bed-8852-ab012603915b"],
+				"idOrganizations": ["1ed53893-6225-4d74-9806-3eedcbb402dd"]
+			}
+		`)),
+	}
+	mockResponse.Header.Set("Content-Type", "application/json")
+
+	// Create a test client with the mock response.
+	testClient := test.NewTestClient(mockResponse, nil)
+
+	// Call GetEnterprise
+	ctx := context.Background()
+	result, annotations, err := testClient.GetEnterprise(ctx, "5e2b6b0f-3e7c-4bd6-9c52-0cf8b2fd1c4e")
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the result.
+	if result == nil {
+		t.Fatal("Expected non-nil result")
+	}
+
+	expectedEnterprise := client.Enterprise{
+		ID:              "5e2b6b0f-3e7c-4bd6-9c52-0cf8b2fd1c4e",
+		Name:            "enterpriseTest",
+		DisplayName:     "Trello Enterprise Test",
+		IdAdmins:        []string{test.UserIDs[0]},
+		IdOrganizations: []string{"1ed53893-6225-4d74-9806-3eedcbb402dd"},
+	}
+
+	if !reflect.DeepEqual(*result, expectedEnterprise) {
+		t.Errorf("Unexpected enterprise: got %+v, want %+v", *result, expectedEnterprise)
+	}
+
+	// Check annotations.
+	if annotations == nil {
+		t.Fatal("Expected non-nil annotations")
+	}
+}
+
+func TestTrelloClient_ListEnterpriseMembers_RequestDetails(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`[]`)),
+			Header:     make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequest = req
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Call ListEnterpriseMembers.
+	ctx := context.Background()
+	_, _, err := testClient.ListEnterpriseMembers(ctx, "enterpriseTest")
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest == nil {
+		t.Fatal("No request was captured")
+	}
+
+	// Check URL components.
+	expectedURL := "https://api.trello.com/1/enterprises/enterpriseTest/members?count=100&fields=fullName%2Cusername%2Cemail&startIndex=0"
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+}
+
+// Tests that enterprise members are read a page at a time until a page comes back short.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-enterprises/#api-enterprises-id-members-get
+func TestTrelloClient_ListEnterpriseMembers_Pages(t *testing.T) {
+	const totalMembers = 130
+
+	var startIndexes []string
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		startIndex, err := strconv.Atoi(req.URL.Query().Get("startIndex"))
+		if err != nil {
+			return nil, err
+		}
+		count, err := strconv.Atoi(req.URL.Query().Get("count"))
+		if err != nil {
+			return nil, err
+		}
+		startIndexes = append(startIndexes, req.URL.Query().Get("startIndex"))
+
+		var members []string
+		for i := startIndex; i < min(startIndex+count, totalMembers); i++ {
+			members = append(members, fmt.Sprintf(`{"id": "member-%d", "username": "tester%d", "email": "tester%d@example.com"}`, i, i, i))
+		}
+
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader("[" + strings.Join(members, ",") + "]")),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+
+	members, _, err := testClient.ListEnterpriseMembers(context.Background(), "enterpriseTest")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(members) != totalMembers {
+		t.Fatalf("Expected %d members, got %d", totalMembers, len(members))
+	}
+	for i, member := range members {
+		if expectedID := fmt.Sprintf("member-%d", i); member.ID != expectedID {
+			t.Errorf("Expected member %s at %d, got %s", expectedID, i, member.ID)
+		}
+	}
+
+	expectedStartIndexes := []string{"0", "100"}
+	if !reflect.DeepEqual(startIndexes, expectedStartIndexes) {
+		t.Errorf("Expected pages starting at %v, got %v", expectedStartIndexes, startIndexes)
+	}
+}
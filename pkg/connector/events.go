@@ -0,0 +1,255 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultEventsPageSize = 100
+	maxEventsPageSize     = 1000
+)
+
+// Membership action types and the member type they grant. Removals map to an empty member type, since the removed
+// member type isn't part of the action.
+var (
+	boardMembershipActions = map[string]string{
+		"addMemberToBoard":        "normal",
+		"makeAdminOfBoard":        "admin",
+		"makeNormalMemberOfBoard": "normal",
+		"makeObserverOfBoard":     "observer",
+		"removeMemberFromBoard":   "",
+	}
+	organizationMembershipActions = map[string]string{
+		"addMemberToOrganization":        "normal",
+		"makeAdminOfOrganization":        "admin",
+		"makeNormalMemberOfOrganization": "normal",
+		"removeMemberFromOrganization":   "",
+	}
+)
+
+// eventSource is a board or organization whose actions are still to be read in the current polling round. Before
+// is set when the actions of the source span several pages.
+type eventSource struct {
+	ResourceTypeID string `json:"resource_type_id"`
+	ResourceID     string `json:"resource_id"`
+	Before         string `json:"before,omitempty"`
+}
+
+// eventCursor is the stream cursor of the event feed. Each polling round reads the actions of every source newer
+// than Since, and the newest action ID seen becomes Since of the next round. Trello action IDs embed their creation
+// time, so comparing them orders actions by date.
+type eventCursor struct {
+	Since   string        `json:"since,omitempty"`
+	Latest  string        `json:"latest,omitempty"`
+	Sources []eventSource `json:"sources,omitempty"`
+}
+
+// ListEvents returns the membership changes of the synced organizations and their boards, one board or organization
+// per page. The first round starts at earliestEvent, and later rounds resume after the newest action already read.
+func (d *Connector) ListEvents(
+	ctx context.Context,
+	earliestEvent *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	cursor := &eventCursor{}
+	if pToken != nil && pToken.Cursor != "" {
+		if err := json.Unmarshal([]byte(pToken.Cursor), cursor); err != nil {
+			return nil, nil, nil, fmt.Errorf("trello-connector: invalid event cursor: %w", err)
+		}
+	}
+
+	if len(cursor.Sources) == 0 {
+		if cursor.Since == "" && earliestEvent != nil {
+			cursor.Since = earliestEvent.AsTime().UTC().Format(time.RFC3339)
+		}
+
+		sources, err := d.eventSources(ctx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cursor.Sources = sources
+	}
+
+	if len(cursor.Sources) == 0 {
+		nextCursor, err := json.Marshal(cursor)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return nil, &pagination.StreamState{Cursor: string(nextCursor), HasMore: false}, nil, nil
+	}
+
+	pageSize := defaultEventsPageSize
+	if pToken != nil && pToken.Size > 0 {
+		pageSize = min(pToken.Size, maxEventsPageSize)
+	}
+
+	source := cursor.Sources[0]
+	cursor.Sources = cursor.Sources[1:]
+
+	actions, annotation, err := d.listActions(ctx, source, client.ActionsQuery{
+		Since:  cursor.Since,
+		Before: source.Before,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var events []*v2.Event
+	for _, action := range actions {
+		if action.ID > cursor.Latest {
+			cursor.Latest = action.ID
+		}
+		events = append(events, actionEvents(&action)...)
+	}
+
+	// A full page means older actions of the source may be left, so the source is read again before the others.
+	if len(actions) == pageSize {
+		source.Before = actions[len(actions)-1].ID
+		cursor.Sources = append([]eventSource{source}, cursor.Sources...)
+	}
+
+	if len(cursor.Sources) == 0 {
+		if cursor.Latest != "" {
+			cursor.Since = cursor.Latest
+		}
+		cursor.Latest = ""
+	}
+
+	nextCursor, err := json.Marshal(cursor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return events, &pagination.StreamState{Cursor: string(nextCursor), HasMore: len(cursor.Sources) > 0}, annotation, nil
+}
+
+// eventSources returns every synced organization followed by its synced boards.
+func (d *Connector) eventSources(ctx context.Context) ([]eventSource, error) {
+	organizationIDs, err := d.client.ListOrganizationIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []eventSource
+	for _, organizationID := range organizationIDs {
+		sources = append(sources, eventSource{ResourceTypeID: organizationResourceType.Id, ResourceID: organizationID})
+
+		boards, _, err := d.client.ListBoards(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, board := range boards {
+			if board.Closed && !d.includeClosedBoards {
+				continue
+			}
+			sources = append(sources, eventSource{ResourceTypeID: boardResourceType.Id, ResourceID: board.ID})
+		}
+	}
+
+	return sources, nil
+}
+
+func (d *Connector) listActions(ctx context.Context, source eventSource, query client.ActionsQuery) ([]client.Action, annotations.Annotations, error) {
+	switch source.ResourceTypeID {
+	case organizationResourceType.Id:
+		query.Filter = actionTypes(organizationMembershipActions)
+		return d.client.ListOrganizationActions(ctx, source.ResourceID, query)
+	case boardResourceType.Id:
+		query.Filter = actionTypes(boardMembershipActions)
+		return d.client.ListBoardActions(ctx, source.ResourceID, query)
+	default:
+		return nil, nil, fmt.Errorf("trello-connector: invalid event source %s", source.ResourceTypeID)
+	}
+}
+
+// actionEvents maps a membership action to grant or revoke events. A removal revokes every member type, since the
+// member type held before the removal is unknown.
+func actionEvents(action *client.Action) []*v2.Event {
+	var (
+		resourceType *v2.ResourceType
+		model        *client.ActionModel
+		memberType   string
+		ok           bool
+		grantPrefix  string
+	)
+
+	if memberType, ok = boardMembershipActions[action.Type]; ok {
+		resourceType, model, grantPrefix = boardResourceType, action.Data.Board, "board-grant"
+	} else if memberType, ok = organizationMembershipActions[action.Type]; ok {
+		resourceType, model, grantPrefix = organizationResourceType, action.Data.Organization, "org-grant"
+	} else {
+		return nil
+	}
+
+	memberID := action.MemberID()
+	if model == nil || model.ID == "" || memberID == "" {
+		return nil
+	}
+
+	entitlementResource := &v2.Resource{
+		Id:          &v2.ResourceId{ResourceType: resourceType.Id, Resource: model.ID},
+		DisplayName: model.Name,
+	}
+	principal := &v2.Resource{
+		Id: &v2.ResourceId{ResourceType: userResourceType.Id, Resource: memberID},
+	}
+	if action.Member != nil {
+		principal.DisplayName = action.Member.Username
+	}
+	occurredAt := timestamppb.New(action.Date)
+
+	if memberType != "" {
+		if action.Data.MemberType != "" {
+			memberType = action.Data.MemberType
+		}
+
+		membershipGrant := grant.NewGrant(entitlementResource, memberType, principal, grant.WithAnnotation(&v2.V1Identifier{
+			Id: fmt.Sprintf("%s:%s:%s:%s", grantPrefix, model.ID, memberID, memberType),
+		}))
+
+		return []*v2.Event{{
+			Id:         action.ID,
+			OccurredAt: occurredAt,
+			Event:      &v2.Event_GrantEvent{GrantEvent: &v2.GrantEvent{Grant: membershipGrant}},
+		}}
+	}
+
+	var events []*v2.Event
+	for _, revokedMemberType := range memberTypes {
+		events = append(events, &v2.Event{
+			Id:         fmt.Sprintf("%s:%s", action.ID, revokedMemberType),
+			OccurredAt: occurredAt,
+			Event: &v2.Event_RevokeEvent{RevokeEvent: &v2.RevokeEvent{
+				Entitlement: entitlement.NewPermissionEntitlement(entitlementResource, revokedMemberType),
+				Principal:   principal,
+			}},
+		})
+	}
+
+	return events
+}
+
+// actionTypes returns the action types of a membership action map, in a stable order.
+func actionTypes(actions map[string]string) []string {
+	types := make([]string, 0, len(actions))
+	for actionType := range actions {
+		types = append(types, actionType)
+	}
+	slices.Sort(types)
+
+	return types
+}
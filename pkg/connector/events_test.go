@@ -0,0 +1,181 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/conductorone/baton-trello/test"
+)
+
+// Tests that membership actions of organizations and boards are mapped to events, based on the documented APIs below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-actions-get
+// https://developer.atlassian.com/cloud/trello/rest/api-group-boards/#api-boards-boardid-actions-get
+func TestConnector_ListEvents(t *testing.T) {
+	bodies := map[string]string{
+		"/1/organizations/organizationTest/boards": `[{"id": "f7a6a858-ab65-4524-9632-b64a21aa3c79", "name": "Finance"}]`,
+		"/1/organizations/organizationTest/actions": `[
+			{
+				"id": "64b7f1a0c0ffee0000000001",
+				"type": "addMemberToOrganization",
+				"date": "2023-07-19T14:00:00.000Z",
+				"data": {
+					"idMemberAdded": "ea960e6c-f613-4bed-8852-ab012603915b",
+					"organization": {"id": "organizationTest", "name": "Trello Workspace Test"}
+				}
+			}
+		]`,
+		"/1/boards/f7a6a858-ab65-4524-9632-b64a21aa3c79/actions": `[
+			{
+				"id": "64b7f1a0c0ffee0000000003",
+				"type": "removeMemberFromBoard",
+				"date": "2023-07-19T14:02:00.000Z",
+				"data": {
+					"idMember": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f",
+					"board": {"id": "f7a6a858-ab65-4524-9632-b64a21aa3c79", "name": "Finance"}
+				}
+			},
+			{
+				"id": "64b7f1a0c0ffee0000000002",
+				"type": "makeAdminOfBoard",
+				"date": "2023-07-19T14:01:00.000Z",
+				"data": {
+					"board": {"id": "f7a6a858-ab65-4524-9632-b64a21aa3c79", "name": "Finance"}
+				},
+				"member": {"id": "ea960e6c-f613-4bed-8852-ab012603915b", "username": "tester1"}
+			}
+		]`,
+	}
+
+	var capturedRequests []*http.Request
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		capturedRequests = append(capturedRequests, req)
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(bodies[req.URL.Path])),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	connector := &Connector{client: client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)}
+
+	// Read the whole round of events.
+	ctx := context.Background()
+	var (
+		eventIDs []string
+		cursor   string
+	)
+	for {
+		events, state, _, err := connector.ListEvents(ctx, nil, &pagination.StreamToken{Cursor: cursor})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, event := range events {
+			eventIDs = append(eventIDs, event.Id)
+		}
+		cursor = state.Cursor
+		if !state.HasMore {
+			break
+		}
+	}
+
+	// Verify the events.
+	expectedEventIDs := []string{
+		"64b7f1a0c0ffee0000000001",
+		"64b7f1a0c0ffee0000000003:admin",
+		"64b7f1a0c0ffee0000000003:normal",
+		"64b7f1a0c0ffee0000000003:observer",
+		"64b7f1a0c0ffee0000000002",
+	}
+	if strings.Join(eventIDs, ",") != strings.Join(expectedEventIDs, ",") {
+		t.Errorf("Unexpected events: got %v, want %v", eventIDs, expectedEventIDs)
+	}
+
+	// Verify the actions are filtered to membership actions.
+	expectedFilter := "addMemberToOrganization,makeAdminOfOrganization,makeNormalMemberOfOrganization,removeMemberFromOrganization"
+	for _, req := range capturedRequests {
+		if req.URL.Path != "/1/organizations/organizationTest/actions" {
+			continue
+		}
+		if filter := req.URL.Query().Get("filter"); filter != expectedFilter {
+			t.Errorf("Expected filter %s, got %s", expectedFilter, filter)
+		}
+	}
+
+	// The next round resumes after the newest action.
+	var nextCursor eventCursor
+	if err := json.Unmarshal([]byte(cursor), &nextCursor); err != nil {
+		t.Fatalf("Expected a valid cursor, got %v", err)
+	}
+	if nextCursor.Since != "64b7f1a0c0ffee0000000003" {
+		t.Errorf("Expected the next round to start after 64b7f1a0c0ffee0000000003, got %s", nextCursor.Since)
+	}
+	if len(nextCursor.Sources) != 0 {
+		t.Errorf("Expected no sources left, got %d", len(nextCursor.Sources))
+	}
+}
+
+// Tests that polling again with the same query returns the actions added since the previous poll.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-actions-get
+func TestConnector_ListEvents_Poll(t *testing.T) {
+	organizationActions := `[]`
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		body := `[]`
+		if req.URL.Path == "/1/organizations/organizationTest/actions" {
+			body = organizationActions
+		}
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	connector := &Connector{client: client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)}
+	ctx := context.Background()
+
+	events, state, _, err := connector.ListEvents(ctx, nil, &pagination.StreamToken{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Expected no events, got %d", len(events))
+	}
+
+	// A member is added before the next poll.
+	organizationActions = `[
+		{
+			"id": "64b7f1a0c0ffee0000000001",
+			"type": "addMemberToOrganization",
+			"date": "2023-07-19T14:00:00.000Z",
+			"data": {
+				"idMemberAdded": "ea960e6c-f613-4bed-8852-ab012603915b",
+				"organization": {"id": "organizationTest", "name": "Trello Workspace Test"}
+			}
+		}
+	]`
+
+	events, _, _, err = connector.ListEvents(ctx, nil, &pagination.StreamToken{Cursor: state.Cursor})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 1 || events[0].Id != "64b7f1a0c0ffee0000000001" {
+		t.Errorf("Expected the new action as an event, got %v", events)
+	}
+}
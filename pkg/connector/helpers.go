@@ -0,0 +1,52 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+)
+
+// entitlementSlug extracts the slug from an entitlement ID. Grants only carry the entitlement ID, so the slug
+// can't be read from the entitlement itself during revocation.
+func entitlementSlug(entitlement *v2.Entitlement) (string, error) {
+	parts := strings.SplitN(entitlement.Id, ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return "", fmt.Errorf("trello-connector: invalid entitlement ID: %s", entitlement.Id)
+	}
+
+	return parts[2], nil
+}
+
+// nextOrganization returns the organization to list on the current page along with the pagination bag holding the
+// organizations left to list. The first page queues every synced organization, so each page lists a single
+// organization. An empty organization ID means there is nothing left to list.
+func nextOrganization(pToken *pagination.Token, resourceTypeID string, organizationIDs []string) (*pagination.Bag, string, error) {
+	bag := &pagination.Bag{}
+
+	var token string
+	if pToken != nil {
+		token = pToken.Token
+	}
+
+	if err := bag.Unmarshal(token); err != nil {
+		return nil, "", err
+	}
+
+	if token == "" {
+		for i := len(organizationIDs) - 1; i >= 0; i-- {
+			bag.Push(pagination.PageState{
+				ResourceTypeID: resourceTypeID,
+				ResourceID:     organizationIDs[i],
+			})
+		}
+	}
+
+	state := bag.Pop()
+	if state == nil {
+		return bag, "", nil
+	}
+
+	return bag, state.ResourceID, nil
+}
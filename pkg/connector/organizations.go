@@ -12,6 +12,8 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	"github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 type organizationBuilder struct {
@@ -19,34 +21,77 @@ type organizationBuilder struct {
 	client           *client.TrelloClient
 	memberships      map[string][]client.User
 	membershipsMutex sync.RWMutex
+	scope            *syncScope
 }
 
-var memberTypes = []string{"admin", "normal", "observer"}
+const (
+	adminMember  = "admin"
+	normalMember = "normal"
+)
+
+var memberTypes = []string{adminMember, normalMember, "observer"}
+
+// activeMember is granted to every member that isn't deactivated. Revoking it deactivates the member instead of
+// removing them, which keeps their history in the organization.
+const activeMember = "active"
 
 func (o *organizationBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 	return organizationResourceType
 }
 
-func (o *organizationBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
-	var resources []*v2.Resource
+// List returns the synced organizations, one organization per page. Organizations that belong to the configured
+// enterprise are listed under the enterprise, every other organization is listed at the top level.
+func (o *organizationBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	organizationIDs, err := o.client.ListOrganizationIDs(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
-	// Note: Trello API only support getting organizations by ID so it is not a bulk operation. Pagination is not needed.
-	organizations, annotation, err := o.client.ListOrganizations(ctx)
+	bag, organizationID, err := nextOrganization(pToken, organizationResourceType.Id, organizationIDs)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if organizationID == "" {
+		return nil, "", nil, nil
+	}
 
+	nextPageToken, err := bag.Marshal()
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, organization := range organizations {
-		orgCopy := organization
-		orgResource, err := parseIntoOrganizationResource(ctx, &orgCopy, nil)
-		if err != nil {
-			return nil, "", nil, err
-		}
-		resources = append(resources, orgResource)
+	// Note: Trello API only support getting organizations by ID so it is not a bulk operation.
+	organization, annotation, err := o.client.GetOrganizationDetail(ctx, organizationID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	orgParentResourceID, err := o.organizationParent(organization)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if parentResourceID.GetResource() != orgParentResourceID.GetResource() {
+		return nil, nextPageToken, annotation, nil
+	}
+
+	orgResource, err := parseIntoOrganizationResource(ctx, organization, orgParentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return []*v2.Resource{orgResource}, nextPageToken, annotation, nil
+}
+
+// organizationParent returns the enterprise an organization belongs to, or nil when it doesn't belong to the
+// configured enterprise.
+func (o *organizationBuilder) organizationParent(organization *client.Organization) (*v2.ResourceId, error) {
+	if o.client.EnterpriseID == "" || organization.IdEnterprise != o.client.EnterpriseID {
+		return nil, nil
 	}
 
-	return resources, "", annotation, nil
+	return resource.NewResourceID(enterpriseResourceType, organization.IdEnterprise)
 }
 
 func parseIntoOrganizationResource(_ context.Context, organization *client.Organization, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
@@ -59,6 +104,10 @@ func parseIntoOrganizationResource(_ context.Context, organization *client.Organ
 		resource.WithGroupProfile(profile),
 	}
 
+	if logoUrl := client.AvatarAssetURL(organization.LogoUrl); logoUrl != "" {
+		groupTraits = append(groupTraits, resource.WithGroupIcon(&v2.AssetRef{Id: logoUrl}))
+	}
+
 	displayName := organization.DisplayName
 
 	ret, err := resource.NewGroupResource(
@@ -67,6 +116,7 @@ func parseIntoOrganizationResource(_ context.Context, organization *client.Organ
 		organization.ID,
 		groupTraits,
 		resource.WithParentResourceID(parentResourceID),
+		resource.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: boardResourceType.Id}),
 	)
 	if err != nil {
 		return nil, err
@@ -87,6 +137,13 @@ func (o *organizationBuilder) Entitlements(_ context.Context, resource *v2.Resou
 		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, memberType, assigmentOptions...))
 	}
 
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Active member of organization %s in Trello", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Organization active member", resource.DisplayName)),
+	}
+	entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, activeMember, assigmentOptions...))
+
 	return entitlements, "", nil, nil
 }
 
@@ -95,6 +152,10 @@ func (o *organizationBuilder) Grants(ctx context.Context, resource *v2.Resource,
 
 	var organizationID = resource.Id.Resource
 
+	if !o.scope.organization(organizationID) {
+		return nil, "", nil, nil
+	}
+
 	// Note: Trello API doesn't support pagination for member queries.
 	err := o.GetMemberships(ctx, organizationID)
 
@@ -102,27 +163,239 @@ func (o *organizationBuilder) Grants(ctx context.Context, resource *v2.Resource,
 		return nil, "", nil, err
 	}
 
-	for _, membership := range o.memberships[organizationID] {
+	o.membershipsMutex.RLock()
+	memberships := o.memberships[organizationID]
+	o.membershipsMutex.RUnlock()
+
+	for _, membership := range memberships {
 		userResource, _ := parseIntoUserResource(ctx, &membership, resource.Id)
 		membershipGrant := grant.NewGrant(resource, membership.MemberType, userResource, grant.WithAnnotation(&v2.V1Identifier{
 			Id: fmt.Sprintf("org-grant:%s:%s:%s", resource.Id.Resource, membership.MemberID, membership.MemberType),
 		}))
 		grants = append(grants, membershipGrant)
+
+		if !membership.Deactivated {
+			activeGrant := grant.NewGrant(resource, activeMember, userResource, grant.WithAnnotation(&v2.V1Identifier{
+				Id: fmt.Sprintf("org-grant:%s:%s:%s", resource.Id.Resource, membership.MemberID, activeMember),
+			}))
+			grants = append(grants, activeGrant)
+		}
 	}
 
 	return grants, "", nil, nil
 }
 
-func newOrganizationBuilder(c *client.TrelloClient) *organizationBuilder {
+// Grant adds the principal to the organization with the entitlement's member type. Members that already belong to
+// the organization have their member type changed instead.
+func (o *organizationBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		l.Warn(
+			"trello-connector: only users can be granted organization membership",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, nil, fmt.Errorf("trello-connector: only users can be granted organization membership")
+	}
+
+	organizationID := entitlement.Resource.Id.Resource
+	memberType, err := entitlementSlug(entitlement)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if memberType == activeMember {
+		return o.reactivateMember(ctx, principal, entitlement)
+	}
+
+	membership, _, err := o.client.GetOrganizationMembership(ctx, organizationID, principal.Id.Resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trello-connector: failed to get organization membership: %w", err)
+	}
+
+	membershipGrant := grant.NewGrant(entitlement.Resource, memberType, principal.Id, grant.WithAnnotation(&v2.V1Identifier{
+		Id: fmt.Sprintf("org-grant:%s:%s:%s", organizationID, principal.Id.Resource, memberType),
+	}))
+
+	if membership != nil && membership.MemberType == memberType {
+		return []*v2.Grant{membershipGrant}, annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	annotation, err := o.client.AddMemberToOrganization(ctx, organizationID, principal.Id.Resource, memberType)
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to add member to organization",
+			zap.String("organization_id", organizationID),
+			zap.String("member_id", principal.Id.Resource),
+			zap.String("member_type", memberType),
+			zap.Error(err),
+		)
+		return nil, nil, fmt.Errorf("trello-connector: failed to add member to organization: %w", err)
+	}
+
+	o.resetMemberships(organizationID)
+
+	return []*v2.Grant{membershipGrant}, annotation, nil
+}
+
+// Revoke removes the member type from the principal. A Trello member holds a single member type per organization:
+// revoking admin demotes the member to a normal member, while revoking normal or observer, the base member types,
+// removes the whole membership. Revoking a member type the member doesn't currently hold changes nothing.
+func (o *organizationBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		l.Warn(
+			"trello-connector: only users can have organization membership revoked",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("trello-connector: only users can have organization membership revoked")
+	}
+
+	organizationID := grant.Entitlement.Resource.Id.Resource
+	memberType, err := entitlementSlug(grant.Entitlement)
+	if err != nil {
+		return nil, err
+	}
+
+	if memberType == activeMember {
+		return o.deactivateMember(ctx, grant)
+	}
+
+	membership, _, err := o.client.GetOrganizationMembership(ctx, organizationID, principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("trello-connector: failed to get organization membership: %w", err)
+	}
+
+	if membership == nil || membership.MemberType != memberType {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	if memberType == adminMember {
+		annotation, err := o.client.AddMemberToOrganization(ctx, organizationID, principal.Id.Resource, normalMember)
+		if err != nil {
+			l.Error(
+				"trello-connector: failed to demote organization admin",
+				zap.String("organization_id", organizationID),
+				zap.String("member_id", principal.Id.Resource),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("trello-connector: failed to demote organization admin: %w", err)
+		}
+
+		o.resetMemberships(organizationID)
+
+		return annotation, nil
+	}
+
+	annotation, err := o.client.RemoveMemberFromOrganization(ctx, organizationID, principal.Id.Resource)
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to remove member from organization",
+			zap.String("organization_id", organizationID),
+			zap.String("member_id", principal.Id.Resource),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("trello-connector: failed to remove member from organization: %w", err)
+	}
+
+	o.resetMemberships(organizationID)
+
+	return annotation, nil
+}
+
+// reactivateMember reactivates a deactivated member. Only existing members can be reactivated, new members have
+// to be granted a member type first.
+func (o *organizationBuilder) reactivateMember(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	organizationID := entitlement.Resource.Id.Resource
+
+	membership, _, err := o.client.GetOrganizationMembership(ctx, organizationID, principal.Id.Resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trello-connector: failed to get organization membership: %w", err)
+	}
+
+	if membership == nil {
+		return nil, nil, fmt.Errorf("trello-connector: member %s doesn't belong to organization %s", principal.Id.Resource, organizationID)
+	}
+
+	activeGrant := grant.NewGrant(entitlement.Resource, activeMember, principal.Id, grant.WithAnnotation(&v2.V1Identifier{
+		Id: fmt.Sprintf("org-grant:%s:%s:%s", organizationID, principal.Id.Resource, activeMember),
+	}))
+
+	if !membership.Deactivated {
+		return []*v2.Grant{activeGrant}, annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	annotation, err := o.client.ReactivateOrganizationMember(ctx, organizationID, principal.Id.Resource)
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to reactivate organization member",
+			zap.String("organization_id", organizationID),
+			zap.String("member_id", principal.Id.Resource),
+			zap.Error(err),
+		)
+		return nil, nil, fmt.Errorf("trello-connector: failed to reactivate organization member: %w", err)
+	}
+
+	o.resetMemberships(organizationID)
+
+	return []*v2.Grant{activeGrant}, annotation, nil
+}
+
+func (o *organizationBuilder) deactivateMember(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	organizationID := grant.Entitlement.Resource.Id.Resource
+	memberID := grant.Principal.Id.Resource
+
+	membership, _, err := o.client.GetOrganizationMembership(ctx, organizationID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("trello-connector: failed to get organization membership: %w", err)
+	}
+
+	if membership == nil || membership.Deactivated {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	annotation, err := o.client.DeactivateOrganizationMember(ctx, organizationID, memberID)
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to deactivate organization member",
+			zap.String("organization_id", organizationID),
+			zap.String("member_id", memberID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("trello-connector: failed to deactivate organization member: %w", err)
+	}
+
+	o.resetMemberships(organizationID)
+
+	return annotation, nil
+}
+
+func newOrganizationBuilder(c *client.TrelloClient, scope *syncScope) *organizationBuilder {
 	return &organizationBuilder{
 		resourceType: organizationResourceType,
 		client:       c,
+		scope:        scope,
 	}
 }
 
+func (o *organizationBuilder) resetMemberships(organizationID string) {
+	o.membershipsMutex.Lock()
+	defer o.membershipsMutex.Unlock()
+
+	delete(o.memberships, organizationID)
+}
+
 func (o *organizationBuilder) GetMemberships(ctx context.Context, organizationID string) error {
-	o.membershipsMutex.RLock()
-	defer o.membershipsMutex.RUnlock()
+	o.membershipsMutex.Lock()
+	defer o.membershipsMutex.Unlock()
 
 	if o.memberships == nil {
 		o.memberships = make(map[string][]client.User)
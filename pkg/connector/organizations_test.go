@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/conductorone/baton-trello/pkg/client"
 	"github.com/conductorone/baton-trello/test"
@@ -107,15 +109,16 @@ func TestTrelloClient_GetOrganizations_RequestDetails(t *testing.T) {
 	}
 
 	// Check URL components.
-	expectedURL := "https://api.trello.com/1/organizations/organizationTest?key=api-key&token=api-token"
+	expectedURL := "https://api.trello.com/1/organizations/organizationTest"
 	if capturedRequest.URL.String() != expectedURL {
 		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
 	}
 
 	// Check headers.
 	expectedHeaders := map[string]string{
-		"Accept":       "application/json",
-		"Content-Type": "application/json",
+		"Accept":        "application/json",
+		"Content-Type":  "application/json",
+		"Authorization": `OAuth oauth_consumer_key="api-key", oauth_token="api-token"`,
 	}
 
 	for key, expectedValue := range expectedHeaders {
@@ -124,3 +127,325 @@ func TestTrelloClient_GetOrganizations_RequestDetails(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the client discovers the organizations of the token's member when none are configured, based on the
+// documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-organizations-get
+func TestTrelloClient_ListOrganizationIDs_Discovery(t *testing.T) {
+	// Create a custom RoundTripper to capture the requests.
+	var capturedRequests []*http.Request
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+				[
+					{"id": "1ed53893-6225-4d74-9806-3eedcbb402dd", "name": "organizationTest"},
+					{"id": "6e1c3b8f-5d04-4a0e-b5a6-0f3f0c8f2a71", "name": "sandbox"}
+				]
+			`)),
+			Header: make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequests = append(capturedRequests, req)
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client without configured organizations, excluding one of them by name.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", nil, baseHttpClient)
+	testClient.ExcludedOrganizationIDs = []string{"sandbox"}
+
+	// Call ListOrganizationIDs twice, the second call should be served from the cache.
+	ctx := context.Background()
+	result, err := testClient.ListOrganizationIDs(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, err = testClient.ListOrganizationIDs(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if len(capturedRequests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(capturedRequests))
+	}
+
+	expectedURL := "https://api.trello.com/1/members/me/organizations?fields=id%2Cname"
+	if capturedRequests[0].URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequests[0].URL.String())
+	}
+
+	// Verify the result.
+	expectedIDs := []string{"1ed53893-6225-4d74-9806-3eedcbb402dd"}
+	if !reflect.DeepEqual(result, expectedIDs) {
+		t.Errorf("Unexpected organization IDs: got %v, want %v", result, expectedIDs)
+	}
+}
+
+// Tests that the client can find a member's organization membership based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-memberships-get
+func TestTrelloClient_GetOrganizationMembership(t *testing.T) {
+	// Create a mock response.
+	mockResponse := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body: io.NopCloser(strings.NewReader(`
+			[
+				{
+					"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2",
+					"idMember": "ea960e6c-f613-4bed-8852-ab012603915b",
+					"memberType": "admin",
+					"unconfirmed": false,
+					"deactivated": false
+				},
+				{
+					"id": "5c0d1d4e-6a8b-4b36-8f0d-1f6f3f6b2e19",
+					"idMember": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f",
+					"memberType": "normal",
+					"unconfirmed": false,
+					"deactivated": false
+				}
+			]
+		`)),
+	}
+	mockResponse.Header.Set("Content-Type", "application/json")
+
+	// Create a test client with the mock response.
+	testClient := test.NewTestClient(mockResponse, nil)
+
+	// Call GetOrganizationMembership
+	ctx := context.Background()
+	membership, _, err := testClient.GetOrganizationMembership(ctx, test.OrganizationIDs[0], test.UserIDs[1])
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the result.
+	if membership == nil {
+		t.Fatal("Expected non-nil membership")
+	}
+
+	expectedMembership := client.User{
+		ID:         "5c0d1d4e-6a8b-4b36-8f0d-1f6f3f6b2e19",
+		MemberID:   test.UserIDs[1],
+		MemberType: "normal",
+	}
+
+	if !reflect.DeepEqual(*membership, expectedMembership) {
+		t.Errorf("Unexpected membership: got %+v, want %+v", *membership, expectedMembership)
+	}
+}
+
+// Tests that the client deactivates members based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-idmember-deactivated-put
+func TestTrelloClient_DeactivateOrganizationMember_RequestDetails(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	var capturedBody []byte
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		capturedRequest = req
+		capturedBody, _ = io.ReadAll(req.Body)
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Call DeactivateOrganizationMember.
+	ctx := context.Background()
+	_, err := testClient.DeactivateOrganizationMember(ctx, test.OrganizationIDs[0], test.UserIDs[0])
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest == nil {
+		t.Fatal("No request was captured")
+	}
+
+	if capturedRequest.Method != http.MethodPut {
+		t.Errorf("Expected method %s, got %s", http.MethodPut, capturedRequest.Method)
+	}
+
+	// Check URL components.
+	expectedURL := "https://api.trello.com/1/organizations/organizationTest/members/" + test.UserIDs[0] + "/deactivated"
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+
+	// Check body.
+	expectedBody := `{"value":true}`
+	if strings.TrimSpace(string(capturedBody)) != expectedBody {
+		t.Errorf("Expected body %s, got %s", expectedBody, string(capturedBody))
+	}
+}
+
+func TestNextOrganization(t *testing.T) {
+	organizationIDs := []string{"organizationTest", "organizationTest2", "organizationTest3"}
+
+	var listed []string
+	pToken := &pagination.Token{}
+	for {
+		bag, organizationID, err := nextOrganization(pToken, organizationResourceType.Id, organizationIDs)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if organizationID == "" {
+			break
+		}
+		listed = append(listed, organizationID)
+
+		nextPageToken, err := bag.Marshal()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if nextPageToken == "" {
+			break
+		}
+		pToken = &pagination.Token{Token: nextPageToken}
+	}
+
+	if !reflect.DeepEqual(listed, organizationIDs) {
+		t.Errorf("Unexpected organizations: got %v, want %v", listed, organizationIDs)
+	}
+}
+
+// Tests that organization grants and revokes change memberships, see the changes made right before them, demote
+// admins instead of removing them, reject principals that aren't users, and report memberships that already match
+// instead of changing them, based on the documented APIs below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-memberships-get
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-idmember-put
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-idmember-delete
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-idmember-deactivated-put
+func TestOrganizationBuilder_GrantRevoke(t *testing.T) {
+	organizationPath := "/organizations/" + test.OrganizationIDs[0]
+	memberPath := organizationPath + "/members/" + test.UserIDs[0]
+	userID := &v2.ResourceId{ResourceType: userResourceType.Id, Resource: test.UserIDs[0]}
+	boardID := &v2.ResourceId{ResourceType: boardResourceType.Id, Resource: test.BoardIDs[0]}
+
+	tests := []struct {
+		name               string
+		memberType         string
+		steps              []grantStep
+		expectedMemberType string
+		expectedRequests   []string
+	}{
+		{
+			name: "grant then revoke",
+			steps: []grantStep{
+				{principal: userID, memberType: normalMember},
+				{revoke: true, principal: userID, memberType: normalMember},
+			},
+			expectedRequests: []string{http.MethodPut + " " + memberPath, http.MethodDelete + " " + memberPath},
+		},
+		{
+			name:       "deactivate then reactivate",
+			memberType: normalMember,
+			steps: []grantStep{
+				{revoke: true, principal: userID, memberType: activeMember},
+				{principal: userID, memberType: activeMember},
+			},
+			expectedMemberType: normalMember,
+			expectedRequests:   []string{http.MethodPut + " " + memberPath + "/deactivated", http.MethodPut + " " + memberPath + "/deactivated"},
+		},
+		{
+			name:               "revoke admin",
+			memberType:         adminMember,
+			steps:              []grantStep{{revoke: true, principal: userID, memberType: adminMember}},
+			expectedMemberType: normalMember,
+			expectedRequests:   []string{http.MethodPut + " " + memberPath},
+		},
+		{
+			name:             "revoke normal",
+			memberType:       normalMember,
+			steps:            []grantStep{{revoke: true, principal: userID, memberType: normalMember}},
+			expectedRequests: []string{http.MethodDelete + " " + memberPath},
+		},
+		{
+			name:             "revoke observer",
+			memberType:       "observer",
+			steps:            []grantStep{{revoke: true, principal: userID, memberType: "observer"}},
+			expectedRequests: []string{http.MethodDelete + " " + memberPath},
+		},
+		{
+			name:               "grant to non-user",
+			memberType:         normalMember,
+			steps:              []grantStep{{principal: boardID, memberType: normalMember, expectedError: true}},
+			expectedMemberType: normalMember,
+		},
+		{
+			name:               "revoke from non-user",
+			memberType:         normalMember,
+			steps:              []grantStep{{revoke: true, principal: boardID, memberType: normalMember, expectedError: true}},
+			expectedMemberType: normalMember,
+		},
+		{
+			name:               "grant already exists",
+			memberType:         normalMember,
+			steps:              []grantStep{{principal: userID, memberType: normalMember, expectedAnnotation: &v2.GrantAlreadyExists{}}},
+			expectedMemberType: normalMember,
+		},
+		{
+			name:               "grant already revoked",
+			memberType:         normalMember,
+			steps:              []grantStep{{revoke: true, principal: userID, memberType: adminMember, expectedAnnotation: &v2.GrantAlreadyRevoked{}}},
+			expectedMemberType: normalMember,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberships := map[string]*test.Membership{}
+			if tt.memberType != "" {
+				memberships[test.UserIDs[0]] = &test.Membership{ID: "membership-1", MemberID: test.UserIDs[0], MemberType: tt.memberType}
+			}
+			server := test.NewMembershipServer(map[string]map[string]*test.Membership{organizationPath: memberships})
+			defer server.Close()
+
+			organizationResource, err := parseIntoOrganizationResource(context.Background(), &client.Organization{ID: test.OrganizationIDs[0], DisplayName: "Trello Workspace Test"}, nil)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			runGrantSteps(t, newOrganizationBuilder(server.Client(), nil), organizationResource, tt.steps)
+
+			membership := server.Membership(organizationPath, test.UserIDs[0])
+			if tt.expectedMemberType == "" && membership != nil {
+				t.Errorf("Expected no membership, got %+v", membership)
+			}
+			if tt.expectedMemberType != "" && (membership == nil || membership.MemberType != tt.expectedMemberType || membership.Deactivated) {
+				t.Errorf("Expected an active %s membership, got %+v", tt.expectedMemberType, membership)
+			}
+
+			if requests := server.Requests(); !reflect.DeepEqual(requests, tt.expectedRequests) {
+				t.Errorf("Expected requests %v, got %v", tt.expectedRequests, requests)
+			}
+		})
+	}
+}
@@ -22,3 +22,15 @@ var boardResourceType = &v2.ResourceType{
 	DisplayName: "Board",
 	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
 }
+
+var enterpriseResourceType = &v2.ResourceType{
+	Id:          "enterprise",
+	DisplayName: "Enterprise",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+}
+
+var tokenResourceType = &v2.ResourceType{
+	Id:          "token",
+	DisplayName: "Token",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
+}
@@ -0,0 +1,233 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const tokenAccess = "access"
+
+type tokenBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.TrelloClient
+	memberID     string
+	memberMutex  sync.Mutex
+}
+
+func (o *tokenBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return tokenResourceType
+}
+
+// List returns the API tokens of the parent user. Trello only shows the tokens of a member to the member itself,
+// so only the tokens of the API token's own member are listed, and other users are skipped without a request.
+func (o *tokenBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if parentResourceID == nil || parentResourceID.ResourceType != userResourceType.Id {
+		return nil, "", nil, nil
+	}
+
+	memberID, err := o.tokenMemberID(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if parentResourceID.Resource != memberID {
+		return nil, "", nil, nil
+	}
+
+	tokens, annotation, err := o.client.ListMemberTokens(ctx, parentResourceID.Resource)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unauthenticated, codes.PermissionDenied, codes.NotFound:
+			l.Debug(
+				"trello-connector: skipping tokens the API token can't read",
+				zap.String("member_id", parentResourceID.Resource),
+				zap.Error(err),
+			)
+			return nil, "", nil, nil
+		default:
+			return nil, "", nil, err
+		}
+	}
+
+	var resources []*v2.Resource
+	for _, token := range tokens {
+		tokenCopy := token
+		tokenResource, err := parseIntoTokenResource(ctx, &tokenCopy, parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		resources = append(resources, tokenResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+// tokenMemberID returns the ID of the member the API token belongs to. It is resolved once and reused for the rest
+// of the sync.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-get
+func (o *tokenBuilder) tokenMemberID(ctx context.Context) (string, error) {
+	o.memberMutex.Lock()
+	defer o.memberMutex.Unlock()
+
+	if o.memberID != "" {
+		return o.memberID, nil
+	}
+
+	member, _, err := o.client.GetMemberDetails(ctx, "me")
+	if err != nil {
+		return "", fmt.Errorf("trello-connector: failed to get the token's member: %w", err)
+	}
+	if member == nil || member.ID == "" {
+		return "", fmt.Errorf("trello-connector: failed to get the token's member")
+	}
+
+	o.memberID = member.ID
+
+	return o.memberID, nil
+}
+
+func parseIntoTokenResource(_ context.Context, token *client.Token, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	var permissions []interface{}
+	for _, permission := range token.Permissions {
+		permissions = append(permissions, tokenPermission(permission))
+	}
+
+	profile := map[string]interface{}{
+		"token_id":    token.ID,
+		"app_name":    token.Identifier,
+		"member_id":   token.MemberID,
+		"permissions": permissions,
+	}
+
+	secretTraits := []resource.SecretTraitOption{
+		withSecretProfile(profile),
+		resource.WithSecretCreatedAt(token.DateCreated),
+		resource.WithSecretIdentityID(parentResourceID),
+	}
+	if token.DateExpires != nil {
+		secretTraits = append(secretTraits, resource.WithSecretExpiresAt(*token.DateExpires))
+	}
+
+	displayName := token.Identifier
+	if displayName == "" {
+		displayName = token.ID
+	}
+
+	ret, err := resource.NewSecretResource(
+		displayName,
+		tokenResourceType,
+		token.ID,
+		secretTraits,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(fmt.Sprintf("Trello API token authorized for %s", displayName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// withSecretProfile sets the profile of a secret trait, which the SDK has no option for.
+func withSecretProfile(profile map[string]interface{}) resource.SecretTraitOption {
+	return func(t *v2.SecretTrait) error {
+		p, err := structpb.NewStruct(profile)
+		if err != nil {
+			return err
+		}
+		t.Profile = p
+
+		return nil
+	}
+}
+
+// tokenPermission describes the access a token grants to a model, e.g. "Board:*:read,write".
+func tokenPermission(permission client.TokenPermission) string {
+	access := "none"
+	switch {
+	case permission.Read && permission.Write:
+		access = "read,write"
+	case permission.Read:
+		access = "read"
+	case permission.Write:
+		access = "write"
+	}
+
+	return fmt.Sprintf("%s:%s:%s", permission.ModelType, permission.ModelID, access)
+}
+
+// Entitlements returns the access entitlement of the token, held by the member that authorized it.
+func (o *tokenBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Access granted through the Trello API token for %s", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Token access", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{entitlement.NewPermissionEntitlement(resource, tokenAccess, assigmentOptions...)}, "", nil, nil
+}
+
+// Grants returns the access grant of the member that authorized the token.
+func (o *tokenBuilder) Grants(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	userResourceID := resource.GetParentResourceId()
+	if userResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	accessGrant := grant.NewGrant(resource, tokenAccess, userResourceID, grant.WithAnnotation(&v2.V1Identifier{
+		Id: fmt.Sprintf("token-grant:%s:%s:%s", resource.Id.Resource, userResourceID.Resource, tokenAccess),
+	}))
+
+	return []*v2.Grant{accessGrant}, "", nil, nil
+}
+
+// Grant always fails, tokens are only minted by members authorizing an application in Trello.
+func (o *tokenBuilder) Grant(_ context.Context, _ *v2.Resource, _ *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	return nil, nil, fmt.Errorf("trello-connector: tokens can't be granted, members authorize applications in Trello")
+}
+
+// Revoke deletes the token, removing the access it granted to its application. Tokens are listed without their
+// value, so they are deleted by the ID they are synced under.
+func (o *tokenBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	tokenID := grant.Entitlement.Resource.Id.Resource
+
+	annotation, err := o.client.DeleteToken(ctx, tokenID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+
+		l.Error(
+			"trello-connector: failed to delete token",
+			zap.String("token_id", tokenID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("trello-connector: failed to delete token: %w", err)
+	}
+
+	return annotation, nil
+}
+
+func newTokenBuilder(c *client.TrelloClient) *tokenBuilder {
+	return &tokenBuilder{
+		resourceType: tokenResourceType,
+		client:       c,
+	}
+}
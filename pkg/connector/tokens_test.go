@@ -0,0 +1,264 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/conductorone/baton-trello/test"
+)
+
+// Tests that the token builder lists a member's tokens as secrets, based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-tokens-get
+func TestTokenBuilder_List(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+				[
+					{
+						"id": "5f8d0d55b54764421b7156c1",
+						"identifier": "Calendar Sync",
+						"idMember": "ea960e6c-f613-4bed-8852-ab012603915b",
+						"dateCreated": "2020-10-19T03:32:37.595Z",
+						"dateExpires": "2030-10-19T03:32:37.595Z",
+						"permissions": [
+							{"idModel": "*", "modelType": "Board", "read": true, "write": true},
+							{"idModel": "*", "modelType": "Organization", "read": true, "write": false}
+						]
+					}
+				]
+			`)),
+			Header: make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/1/members/me" {
+			return tokenMemberResponse(test.UserIDs[0]), nil
+		}
+
+		capturedRequest = req
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// List the tokens of a user.
+	ctx := context.Background()
+	userResourceID, err := resource.NewResourceID(userResourceType, test.UserIDs[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resources, _, _, err := newTokenBuilder(testClient).List(ctx, userResourceID, nil)
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	expectedURL := "https://api.trello.com/1/members/ea960e6c-f613-4bed-8852-ab012603915b/tokens"
+	if capturedRequest.URL.String() != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	}
+
+	// Verify the result.
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 token, got %d", len(resources))
+	}
+
+	tokenResource := resources[0]
+	if tokenResource.DisplayName != "Calendar Sync" {
+		t.Errorf("Expected display name Calendar Sync, got %s", tokenResource.DisplayName)
+	}
+	if tokenResource.ParentResourceId.GetResource() != test.UserIDs[0] {
+		t.Errorf("Expected parent %s, got %s", test.UserIDs[0], tokenResource.ParentResourceId.GetResource())
+	}
+
+	secretTrait := &v2.SecretTrait{}
+	tokenAnnotations := annotations.Annotations(tokenResource.Annotations)
+	ok, err := tokenAnnotations.Pick(secretTrait)
+	if err != nil || !ok {
+		t.Fatalf("Expected a secret trait, got %v", err)
+	}
+
+	expectedExpiry := time.Date(2030, 10, 19, 3, 32, 37, 595000000, time.UTC)
+	if !secretTrait.ExpiresAt.AsTime().Equal(expectedExpiry) {
+		t.Errorf("Expected expiry %s, got %s", expectedExpiry, secretTrait.ExpiresAt.AsTime())
+	}
+	if secretTrait.IdentityId.GetResource() != test.UserIDs[0] {
+		t.Errorf("Expected identity %s, got %s", test.UserIDs[0], secretTrait.IdentityId.GetResource())
+	}
+
+	appName, _ := resource.GetProfileStringValue(secretTrait.Profile, "app_name")
+	if appName != "Calendar Sync" {
+		t.Errorf("Expected app name Calendar Sync, got %s", appName)
+	}
+
+	permissions := secretTrait.Profile.GetFields()["permissions"].GetListValue().AsSlice()
+	expectedPermissions := []interface{}{"Board:*:read,write", "Organization:*:read"}
+	if len(permissions) != len(expectedPermissions) || permissions[0] != expectedPermissions[0] || permissions[1] != expectedPermissions[1] {
+		t.Errorf("Unexpected permissions: got %v, want %v", permissions, expectedPermissions)
+	}
+}
+
+// tokenMemberResponse returns the response of members/me for the member the API token belongs to.
+func tokenMemberResponse(memberID string) *http.Response {
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"id": "` + memberID + `", "username": "tester"}`)),
+	}
+	response.Header.Set("Content-Type", "application/json")
+	return response
+}
+
+// Tests that the tokens of members the API token can't see are skipped instead of failing the sync.
+func TestTokenBuilder_List_Unauthorized(t *testing.T) {
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/1/members/me" {
+			return tokenMemberResponse(test.UserIDs[1]), nil
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`unauthorized permission requested`)),
+		}, nil
+	})
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport}))
+
+	userResourceID, err := resource.NewResourceID(userResourceType, test.UserIDs[1])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	resources, _, _, err := newTokenBuilder(testClient).List(context.Background(), userResourceID, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("Expected no tokens, got %d", len(resources))
+	}
+}
+
+// Tests that only the tokens of the API token's own member are requested, resolving the member once.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-members/#api-members-id-get
+func TestTokenBuilder_List_OtherMembers(t *testing.T) {
+	var requests []string
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.URL.Path)
+		if req.URL.Path == "/1/members/me" {
+			return tokenMemberResponse(test.UserIDs[0]), nil
+		}
+
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`[]`)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport}))
+	builder := newTokenBuilder(testClient)
+
+	for _, userID := range []string{test.UserIDs[1], test.UserIDs[0]} {
+		userResourceID, err := resource.NewResourceID(userResourceType, userID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, _, _, err := builder.List(context.Background(), userResourceID, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	expectedRequests := []string{"/1/members/me", "/1/members/" + test.UserIDs[0] + "/tokens"}
+	if !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("Expected requests %v, got %v", expectedRequests, requests)
+	}
+}
+
+// Tests that revoking a token's access deletes the token by the ID it was listed with, based on the documented API
+// below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-delete
+func TestTokenBuilder_Revoke(t *testing.T) {
+	const tokenID = "5f8d0d55b54764421b7156c1"
+
+	testCases := []struct {
+		name               string
+		statusCode         int
+		expectedError      bool
+		expectedAnnotation bool
+	}{
+		{"deleted", http.StatusOK, false, false},
+		{"already deleted", http.StatusNotFound, false, true},
+		{"failed", http.StatusInternalServerError, true, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var capturedRequest *http.Request
+			mockTransport := &test.MockRoundTripper{}
+			mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+				capturedRequest = req
+				response := &http.Response{
+					StatusCode: testCase.statusCode,
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(`{"_value": null}`)),
+				}
+				response.Header.Set("Content-Type", "application/json")
+				return response, nil
+			})
+			testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport}))
+
+			userResourceID, err := resource.NewResourceID(userResourceType, test.UserIDs[0])
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			tokenResource, err := parseIntoTokenResource(context.Background(), &client.Token{ID: tokenID, Identifier: "Calendar Sync"}, userResourceID)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			annos, err := newTokenBuilder(testClient).Revoke(context.Background(), grant.NewGrant(tokenResource, tokenAccess, userResourceID))
+			if testCase.expectedError != (err != nil) {
+				t.Fatalf("Expected error: %t, got %v", testCase.expectedError, err)
+			}
+
+			if capturedRequest.Method != http.MethodDelete {
+				t.Errorf("Expected method %s, got %s", http.MethodDelete, capturedRequest.Method)
+			}
+			expectedURL := "https://api.trello.com/1/tokens/" + tokenID
+			if capturedRequest.URL.String() != expectedURL {
+				t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+			}
+
+			if annos.Contains(&v2.GrantAlreadyRevoked{}) != testCase.expectedAnnotation {
+				t.Errorf("Expected already revoked: %t, got %v", testCase.expectedAnnotation, annos)
+			}
+		})
+	}
+}
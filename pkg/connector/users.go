@@ -2,18 +2,25 @@ package connector
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/conductorone/baton-trello/pkg/client"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 type userBuilder struct {
-	resourceType *v2.ResourceType
-	client       *client.TrelloClient
+	resourceType   *v2.ResourceType
+	client         *client.TrelloClient
+	personalBoards bool
+	scope          *syncScope
 }
 
 func (o *userBuilder) ResourceType(_ context.Context) *v2.ResourceType {
@@ -22,51 +29,114 @@ func (o *userBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 
 // List returns all the users from the database as resource objects.
 // Users include a UserTrait because they are the 'shape' of a standard user.
-func (o *userBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+// Each page lists the users of a single organization.
+// When personal boards are synced, users are the parents of the personal boards they own.
+func (o *userBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var resources []*v2.Resource
 
+	organizationIDs, err := o.client.ListOrganizationIDs(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, organizationID, err := nextOrganization(pToken, userResourceType.Id, organizationIDs)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if organizationID == "" {
+		return nil, "", nil, nil
+	}
+
 	// Note: Trello API doesn't support pagination for member queries.
-	users, annotation, err := o.client.ListUsers(ctx)
+	users, annotation, err := o.client.ListUsers(ctx, organizationID)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
+	var userOptions []resource.ResourceOption
+	if o.personalBoards {
+		userOptions = append(userOptions, resource.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: boardResourceType.Id}))
+	}
+
 	for _, user := range users {
+		if !o.scope.member(user.ID) {
+			continue
+		}
+
 		userCopy := user
-		userResource, err := parseIntoUserResource(ctx, &userCopy, nil)
+		userResource, err := parseIntoUserResource(ctx, &userCopy, nil, userOptions...)
 		if err != nil {
 			return nil, "", nil, err
 		}
 		resources = append(resources, userResource)
 	}
 
-	return resources, "", annotation, nil
-}
+	nextPageToken, err := bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
 
-func parseIntoUserResource(_ context.Context, user *client.User, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
-	var userStatus = v2.UserTrait_Status_STATUS_ENABLED
+	return resources, nextPageToken, annotation, nil
+}
 
+func parseIntoUserResource(
+	_ context.Context,
+	user *client.User,
+	parentResourceID *v2.ResourceId,
+	opts ...resource.ResourceOption,
+) (*v2.Resource, error) {
 	profile := map[string]interface{}{
 		"user_id":     user.ID,
 		"username":    user.Username,
 		"full_name":   user.Name,
 		"member_type": user.MemberType,
+		"deactivated": user.Deactivated,
+		"unconfirmed": user.Unconfirmed,
 	}
 
 	userTraits := []resource.UserTraitOption{
-		resource.WithUserProfile(profile),
-		resource.WithStatus(userStatus),
+		userStatus(user),
 		resource.WithUserLogin(user.Username),
 	}
 
+	if user.Email != "" {
+		profile["email"] = user.Email
+		userTraits = append(userTraits, resource.WithEmail(user.Email, true))
+	}
+
+	if lastActive := user.LastActivity(); lastActive != nil {
+		profile["last_active"] = lastActive.Format(time.RFC3339)
+		userTraits = append(userTraits, resource.WithLastLogin(*lastActive))
+	}
+
+	if createdAt, ok := user.CreatedAt(); ok {
+		profile["created_at"] = createdAt.Format(time.RFC3339)
+		userTraits = append(userTraits, resource.WithCreatedAt(createdAt))
+	}
+
+	if avatarUrl := client.AvatarAssetURL(user.AvatarUrl); avatarUrl != "" {
+		userTraits = append(userTraits, resource.WithUserIcon(&v2.AssetRef{Id: avatarUrl}))
+	}
+
+	userTraits = append(userTraits, resource.WithUserProfile(profile))
+
 	displayName := user.Username
 
+	opts = append(
+		[]resource.ResourceOption{
+			resource.WithParentResourceID(parentResourceID),
+			resource.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: tokenResourceType.Id}),
+		},
+		opts...,
+	)
+
 	ret, err := resource.NewUserResource(
 		displayName,
 		userResourceType,
 		user.ID,
 		userTraits,
-		resource.WithParentResourceID(parentResourceID),
+		opts...,
 	)
 	if err != nil {
 		return nil, err
@@ -75,6 +145,19 @@ func parseIntoUserResource(_ context.Context, user *client.User, parentResourceI
 	return ret, nil
 }
 
+// userStatus maps the membership flags of a Trello member to a user status. Deactivated members keep their history
+// but can no longer access the workspace, and unconfirmed members haven't accepted their invitation yet.
+func userStatus(user *client.User) resource.UserTraitOption {
+	switch {
+	case user.Deactivated:
+		return resource.WithDetailedStatus(v2.UserTrait_Status_STATUS_DISABLED, "member is deactivated in the workspace")
+	case user.Unconfirmed:
+		return resource.WithDetailedStatus(v2.UserTrait_Status_STATUS_DISABLED, "member has not confirmed their invitation")
+	default:
+		return resource.WithStatus(v2.UserTrait_Status_STATUS_ENABLED)
+	}
+}
+
 // Entitlements always returns an empty slice for users.
 func (o *userBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
 	return nil, "", nil, nil
@@ -85,9 +168,90 @@ func (o *userBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.To
 	return nil, "", nil, nil
 }
 
-func newUserBuilder(c *client.TrelloClient) *userBuilder {
+// CreateAccount invites a person to a Trello organization by email. The organization defaults to the first
+// synced one unless the account profile names another.
+func (o *userBuilder) CreateAccount(
+	ctx context.Context,
+	accountInfo *v2.AccountInfo,
+	_ *v2.CredentialOptions,
+) (connectorbuilder.CreateAccountResponse, []*v2.PlaintextData, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	email := accountEmail(accountInfo)
+	if email == "" {
+		return nil, nil, nil, fmt.Errorf("trello-connector: an email is required to invite a user")
+	}
+
+	fullName, _ := resource.GetProfileStringValue(accountInfo.Profile, "full_name")
+	if fullName == "" {
+		return nil, nil, nil, fmt.Errorf("trello-connector: a full name is required to invite a user")
+	}
+
+	organizationID, _ := resource.GetProfileStringValue(accountInfo.Profile, "organization")
+	if organizationID == "" {
+		organizationIDs, err := o.client.ListOrganizationIDs(ctx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(organizationIDs) == 0 {
+			return nil, nil, nil, fmt.Errorf("trello-connector: no organization available to invite the user into")
+		}
+		organizationID = organizationIDs[0]
+	}
+
+	user, annotation, err := o.client.InviteMemberToOrganization(ctx, organizationID, email, fullName)
+	if err != nil {
+		l.Error(
+			"trello-connector: failed to invite user",
+			zap.String("organization_id", organizationID),
+			zap.String("email", email),
+			zap.Error(err),
+		)
+		return nil, nil, nil, fmt.Errorf("trello-connector: failed to invite user: %w", err)
+	}
+
+	userResource, err := parseIntoUserResource(ctx, user, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &v2.CreateAccountResponse_SuccessResult{
+		Resource:              userResource,
+		IsCreateAccountResult: true,
+	}, nil, annotation, nil
+}
+
+// CreateAccountCapabilityDetails reports that invited users set their own password when accepting the invitation.
+func (o *userBuilder) CreateAccountCapabilityDetails(_ context.Context) (*v2.CredentialDetailsAccountProvisioning, annotations.Annotations, error) {
+	return &v2.CredentialDetailsAccountProvisioning{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+	}, nil, nil
+}
+
+// accountEmail returns the primary email of the account, falling back to the first email or the profile.
+func accountEmail(accountInfo *v2.AccountInfo) string {
+	for _, email := range accountInfo.GetEmails() {
+		if email.GetIsPrimary() {
+			return email.GetAddress()
+		}
+	}
+
+	if len(accountInfo.GetEmails()) > 0 {
+		return accountInfo.GetEmails()[0].GetAddress()
+	}
+
+	email, _ := resource.GetProfileStringValue(accountInfo.GetProfile(), "email")
+	return email
+}
+
+func newUserBuilder(c *client.TrelloClient, personalBoards bool, scope *syncScope) *userBuilder {
 	return &userBuilder{
-		resourceType: userResourceType,
-		client:       c,
+		resourceType:   userResourceType,
+		client:         c,
+		personalBoards: personalBoards,
+		scope:          scope,
 	}
 }
@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/conductorone/baton-trello/pkg/client"
 	"github.com/conductorone/baton-trello/test"
@@ -30,7 +36,7 @@ func TestTrelloClient_GetUsers(t *testing.T) {
 
 	// Call GetUsers
 	ctx := context.Background()
-	result, nextOptions, err := testClient.ListUsers(ctx)
+	result, nextOptions, err := testClient.ListUsers(ctx, test.OrganizationIDs[0])
 
 	// Check for errors.
 	if err != nil {
@@ -47,11 +53,17 @@ func TestTrelloClient_GetUsers(t *testing.T) {
 		t.Errorf("Expected Count to be 2, got %d", len(result))
 	}
 
+	lastActive := []time.Time{
+		time.Date(2025, 2, 5, 17, 34, 3, 386000000, time.UTC),
+		time.Date(2025, 2, 3, 12, 48, 18, 512000000, time.UTC),
+	}
+
 	for index, user := range result {
 		expectedUser := client.User{
-			ID:       test.UserIDs[index],
-			Username: fmt.Sprintf("tester%d", index+1),
-			Name:     fmt.Sprintf("Test User %d", index+1),
+			ID:         test.UserIDs[index],
+			Username:   fmt.Sprintf("tester%d", index+1),
+			Name:       fmt.Sprintf("Test User %d", index+1),
+			LastActive: &lastActive[index],
 		}
 
 		if !reflect.DeepEqual(user, expectedUser) {
@@ -66,12 +78,11 @@ func TestTrelloClient_GetUsers(t *testing.T) {
 }
 
 func TestTrelloClient_GetUsers_RequestDetails(t *testing.T) {
-	// Create a custom RoundTripper to capture the request.
-	var capturedRequest *http.Request
+	// Create a custom RoundTripper to capture the requests.
+	var capturedRequests []*http.Request
 	mockTransport := &test.MockRoundTripper{
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
-			Body:       io.NopCloser(strings.NewReader(`[]`)),
 			Header:     make(http.Header),
 		},
 		Err: nil,
@@ -79,8 +90,10 @@ func TestTrelloClient_GetUsers_RequestDetails(t *testing.T) {
 	mockTransport.Response.Header.Set("Content-Type", "application/json")
 
 	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
-		capturedRequest = req
-		return mockTransport.Response, mockTransport.Err
+		capturedRequests = append(capturedRequests, req)
+		response := *mockTransport.Response
+		response.Body = io.NopCloser(strings.NewReader(`[]`))
+		return &response, mockTransport.Err
 	}
 	mockTransport.SetRoundTrip(mockRoundTrip)
 
@@ -91,7 +104,7 @@ func TestTrelloClient_GetUsers_RequestDetails(t *testing.T) {
 
 	// Call GetUsers.
 	ctx := context.Background()
-	_, _, err := testClient.ListUsers(ctx)
+	_, _, err := testClient.ListUsers(ctx, test.OrganizationIDs[0])
 
 	// Check for errors.
 	if err != nil {
@@ -99,24 +112,44 @@ func TestTrelloClient_GetUsers_RequestDetails(t *testing.T) {
 	}
 
 	// Verify the request details.
-	if capturedRequest == nil {
-		t.Fatal("No request was captured")
+	if len(capturedRequests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(capturedRequests))
 	}
 
-	// Check URL components.
-	expectedURL := "https://api.trello.com/1/organizations/organizationTest/members?key=api-key&token=api-token"
-	if capturedRequest.URL.String() != expectedURL {
-		t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequest.URL.String())
+	// Check URL components. Members are listed first, then their memberships are fetched for the member status, and
+	// the token's own member is fetched for its email.
+	expectedURLs := []string{
+		"https://api.trello.com/1/organizations/organizationTest/members?fields=fullName%2Cusername%2CavatarUrl%2Cemail%2CdateLastActive",
+		"https://api.trello.com/1/organizations/organizationTest/memberships",
+		"https://api.trello.com/1/members/me",
+	}
+	for index, expectedURL := range expectedURLs {
+		if capturedRequests[index].URL.String() != expectedURL {
+			t.Errorf("Expected URL %s, got %s", expectedURL, capturedRequests[index].URL.String())
+		}
+	}
+
+	// Check every member field user resources are built from is requested: the name, login, avatar, email and last
+	// activity.
+	requestedFields := map[string]bool{}
+	for _, field := range strings.Split(capturedRequests[0].URL.Query().Get("fields"), ",") {
+		requestedFields[field] = true
+	}
+	for _, field := range []string{"fullName", "username", "avatarUrl", "email", "dateLastActive"} {
+		if !requestedFields[field] {
+			t.Errorf("Expected field %s to be requested", field)
+		}
 	}
 
 	// Check headers.
 	expectedHeaders := map[string]string{
-		"Accept":       "application/json",
-		"Content-Type": "application/json",
+		"Accept":        "application/json",
+		"Content-Type":  "application/json",
+		"Authorization": `OAuth oauth_consumer_key="api-key", oauth_token="api-token"`,
 	}
 
 	for key, expectedValue := range expectedHeaders {
-		if value := capturedRequest.Header.Get(key); value != expectedValue {
+		if value := capturedRequests[0].Header.Get(key); value != expectedValue {
 			t.Errorf("Expected header %s to be %s, got %s", key, expectedValue, value)
 		}
 	}
@@ -161,10 +194,12 @@ func TestTrelloClient_GetUserDetails(t *testing.T) {
 		t.Fatal("Expected non-nil result")
 	}
 
+	lastActive := time.Date(2025, 2, 5, 17, 34, 3, 386000000, time.UTC)
 	expectedUser := client.User{
 		ID:         userID,
 		MemberID:   userID,
 		MemberType: "normal",
+		LastActive: &lastActive,
 	}
 
 	if !reflect.DeepEqual(*user, expectedUser) {
@@ -176,3 +211,395 @@ func TestTrelloClient_GetUserDetails(t *testing.T) {
 		t.Fatal("Expected non-nil annotations")
 	}
 }
+
+// Tests that the client can invite users based on the documented API below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-put
+func TestTrelloClient_InviteMemberToOrganization(t *testing.T) {
+	// Create a custom RoundTripper to capture the request.
+	var capturedRequest *http.Request
+	var capturedBody []byte
+	mockTransport := &test.MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+				{
+					"id": "1ed53893-6225-4d74-9806-3eedcbb402dd",
+					"members": [
+						{
+							"id": "ea960e6c-f613-4bed-8852-ab012603915b",
+							"fullName": "Test User 1",
+							"username": "tester1"
+						},
+						{
+							"id": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f",
+							"fullName": "Test User 2",
+							"username": "tester2"
+						}
+					],
+					"memberships": [
+						{
+							"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2",
+							"idMember": "ea960e6c-f613-4bed-8852-ab012603915b",
+							"memberType": "admin"
+						},
+						{
+							"id": "5c0d1d4e-6a8b-4b36-8f0d-1f6f3f6b2e19",
+							"idMember": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f",
+							"memberType": "normal",
+							"unconfirmed": true
+						}
+					]
+				}
+			`)),
+			Header: make(http.Header),
+		},
+		Err: nil,
+	}
+	mockTransport.Response.Header.Set("Content-Type", "application/json")
+
+	// The organization only has the first member before the invitation.
+	mockRoundTrip := func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			response := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body: io.NopCloser(strings.NewReader(`[
+					{"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2", "idMember": "ea960e6c-f613-4bed-8852-ab012603915b", "memberType": "admin"}
+				]`)),
+			}
+			response.Header.Set("Content-Type", "application/json")
+			return response, nil
+		}
+
+		capturedRequest = req
+		capturedBody, _ = io.ReadAll(req.Body)
+		return mockTransport.Response, mockTransport.Err
+	}
+	mockTransport.SetRoundTrip(mockRoundTrip)
+
+	// Create a test client with the mock transport.
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+
+	// Call InviteMemberToOrganization.
+	ctx := context.Background()
+	user, _, err := testClient.InviteMemberToOrganization(ctx, test.OrganizationIDs[0], "tester2@example.com", "Test User 2")
+
+	// Check for errors.
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Verify the request details.
+	if capturedRequest.Method != http.MethodPut {
+		t.Errorf("Expected method %s, got %s", http.MethodPut, capturedRequest.Method)
+	}
+
+	expectedBody := `{"email":"tester2@example.com","fullName":"Test User 2","type":"normal"}`
+	if strings.TrimSpace(string(capturedBody)) != expectedBody {
+		t.Errorf("Expected body %s, got %s", expectedBody, string(capturedBody))
+	}
+
+	// Verify the result.
+	expectedUser := client.User{
+		ID:          test.UserIDs[1],
+		Username:    "tester2",
+		Name:        "Test User 2",
+		Email:       "tester2@example.com",
+		MemberType:  "normal",
+		Unconfirmed: true,
+	}
+
+	if !reflect.DeepEqual(*user, expectedUser) {
+		t.Errorf("Unexpected user: got %+v, want %+v", *user, expectedUser)
+	}
+
+	// The invited member hasn't accepted the invitation yet.
+	userResource, err := parseIntoUserResource(ctx, user, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	userTrait, err := resource.GetUserTrait(userResource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if userTrait.GetStatus().GetStatus() != v2.UserTrait_Status_STATUS_DISABLED {
+		t.Errorf("Expected status %s, got %s", v2.UserTrait_Status_STATUS_DISABLED, userTrait.GetStatus().GetStatus())
+	}
+}
+
+// Tests that an invited member is told apart from an existing member with the same full name.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-members-put
+func TestTrelloClient_InviteMemberToOrganization_DuplicateName(t *testing.T) {
+	memberships := `[
+		{"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2", "idMember": "ea960e6c-f613-4bed-8852-ab012603915b", "memberType": "normal"}
+	]`
+	invited := `{
+		"id": "1ed53893-6225-4d74-9806-3eedcbb402dd",
+		"members": [
+			{"id": "ea960e6c-f613-4bed-8852-ab012603915b", "fullName": "Test User", "username": "tester1"},
+			{"id": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f", "fullName": "Test User", "username": "tester2"}
+		],
+		"memberships": [
+			{"id": "0b4b7f8e-0a3c-4c8a-9d0e-1a8d51c7e7a2", "idMember": "ea960e6c-f613-4bed-8852-ab012603915b", "memberType": "normal"},
+			{"id": "0a1d6f3c-2b7e-4c59-9e8a-7d4f0b2c1e35", "idMember": "8b21d0aa-39a4-4c09-86d2-d29dff8d261f", "memberType": "normal", "unconfirmed": true}
+		]
+	}`
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		body := invited
+		if req.Method == http.MethodGet {
+			body = memberships
+		}
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, uhttp.NewBaseHttpClient(httpClient))
+
+	user, _, err := testClient.InviteMemberToOrganization(context.Background(), test.OrganizationIDs[0], "tester2@example.com", "Test User")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if user.ID != test.UserIDs[1] {
+		t.Errorf("Expected the invited member %s, got %s", test.UserIDs[1], user.ID)
+	}
+	if user.Email != "tester2@example.com" {
+		t.Errorf("Expected email tester2@example.com, got %s", user.Email)
+	}
+}
+
+func TestParseIntoUserResource_Status(t *testing.T) {
+	testCases := []struct {
+		name           string
+		user           client.User
+		expectedStatus v2.UserTrait_Status_Status
+	}{
+		{"active", client.User{ID: test.UserIDs[0]}, v2.UserTrait_Status_STATUS_ENABLED},
+		{"deactivated", client.User{ID: test.UserIDs[0], Deactivated: true}, v2.UserTrait_Status_STATUS_DISABLED},
+		{"unconfirmed", client.User{ID: test.UserIDs[0], Unconfirmed: true}, v2.UserTrait_Status_STATUS_DISABLED},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			userResource, err := parseIntoUserResource(context.Background(), &testCase.user, nil)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			userTrait, err := resource.GetUserTrait(userResource)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if userTrait.GetStatus().GetStatus() != testCase.expectedStatus {
+				t.Errorf("Expected status %s, got %s", testCase.expectedStatus, userTrait.GetStatus().GetStatus())
+			}
+		})
+	}
+}
+
+// Tests that a member is only disabled when it is deactivated or unconfirmed in every synced organization, whichever
+// organization it is listed under.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-organizations/#api-organizations-id-memberships-get
+func TestUserBuilder_List_StatusAcrossOrganizations(t *testing.T) {
+	organizationIDs := []string{test.OrganizationIDs[0], "otherOrganization"}
+	members := `[
+		{"id": "` + test.UserIDs[0] + `", "username": "tester1"},
+		{"id": "` + test.UserIDs[1] + `", "username": "tester2"}
+	]`
+	memberships := map[string]string{
+		organizationIDs[0]: `[
+			{"id": "membership1", "idMember": "` + test.UserIDs[0] + `", "memberType": "normal", "deactivated": true},
+			{"id": "membership2", "idMember": "` + test.UserIDs[1] + `", "memberType": "normal", "unconfirmed": true}
+		]`,
+		organizationIDs[1]: `[
+			{"id": "membership3", "idMember": "` + test.UserIDs[0] + `", "memberType": "normal"},
+			{"id": "membership4", "idMember": "` + test.UserIDs[1] + `", "memberType": "normal", "deactivated": true}
+		]`,
+	}
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		segments := strings.Split(strings.TrimPrefix(req.URL.Path, "/1/organizations/"), "/")
+		body := `[]`
+		if len(segments) == 2 && segments[1] == "members" {
+			body = members
+		}
+		if len(segments) == 2 && segments[1] == "memberships" {
+			body = memberships[segments[0]]
+		}
+
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	httpClient := &http.Client{Transport: mockTransport}
+	testClient := client.NewClient("api-key", "api-token", organizationIDs, uhttp.NewBaseHttpClient(httpClient))
+	builder := newUserBuilder(testClient, false, nil)
+
+	expectedStatuses := map[string]v2.UserTrait_Status_Status{
+		test.UserIDs[0]: v2.UserTrait_Status_STATUS_ENABLED,
+		test.UserIDs[1]: v2.UserTrait_Status_STATUS_DISABLED,
+	}
+
+	pToken := &pagination.Token{}
+	for page := 0; page < len(organizationIDs); page++ {
+		users, nextPageToken, _, err := builder.List(context.Background(), nil, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(users) != len(expectedStatuses) {
+			t.Fatalf("Expected %d users, got %d", len(expectedStatuses), len(users))
+		}
+
+		for _, user := range users {
+			userTrait, err := resource.GetUserTrait(user)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			expectedStatus := expectedStatuses[user.Id.Resource]
+			if userTrait.GetStatus().GetStatus() != expectedStatus {
+				t.Errorf("Expected status %s for %s on page %d, got %s", expectedStatus, user.Id.Resource, page, userTrait.GetStatus().GetStatus())
+			}
+		}
+
+		pToken = &pagination.Token{Token: nextPageToken}
+	}
+}
+
+// Tests that the last activity and the creation time embedded in the member ID are mapped to the user trait.
+func TestParseIntoUserResource_Activity(t *testing.T) {
+	lastActive := time.Date(2025, 2, 5, 17, 34, 3, 0, time.UTC)
+	user := client.User{
+		ID:         "5f8d0d55b54764421b7156c1",
+		Username:   "tester1",
+		LastActive: &lastActive,
+	}
+
+	userResource, err := parseIntoUserResource(context.Background(), &user, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	userTrait, err := resource.GetUserTrait(userResource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !userTrait.GetLastLogin().AsTime().Equal(lastActive) {
+		t.Errorf("Expected last login %s, got %s", lastActive, userTrait.GetLastLogin().AsTime())
+	}
+
+	expectedCreatedAt := time.Date(2020, 10, 19, 3, 51, 49, 0, time.UTC)
+	if !userTrait.GetCreatedAt().AsTime().Equal(expectedCreatedAt) {
+		t.Errorf("Expected creation time %s, got %s", expectedCreatedAt, userTrait.GetCreatedAt().AsTime())
+	}
+
+	expectedProfile := map[string]string{
+		"last_active": "2025-02-05T17:34:03Z",
+		"created_at":  "2020-10-19T03:51:49Z",
+	}
+	for key, expectedValue := range expectedProfile {
+		if value, _ := resource.GetProfileStringValue(userTrait.GetProfile(), key); value != expectedValue {
+			t.Errorf("Expected profile %s to be %s, got %s", key, expectedValue, value)
+		}
+	}
+
+	// Members with IDs that aren't object IDs have no creation time.
+	user.ID = test.UserIDs[0]
+	userResource, err = parseIntoUserResource(context.Background(), &user, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	userTrait, err = resource.GetUserTrait(userResource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if userTrait.GetCreatedAt() != nil {
+		t.Errorf("Expected no creation time, got %s", userTrait.GetCreatedAt().AsTime())
+	}
+}
+
+// Tests that users get the email the token can see, falling back to the username to email mapping file.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-enterprises/#api-enterprises-id-members-get
+func TestTrelloClient_GetUsers_Emails(t *testing.T) {
+	bodies := map[string]string{
+		"/1/organizations/organizationTest/members":     test.ReadFile("usersMock.json"),
+		"/1/organizations/organizationTest/memberships": `[]`,
+		"/1/members/me":                         `{"id": "` + test.UserIDs[0] + `", "username": "tester1", "email": "tester1@example.com"}`,
+		"/1/enterprises/enterpriseTest/members": `[]`,
+	}
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(bodies[req.URL.Path])),
+		}
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	// Write a mapping file for the member whose email the token can't see.
+	mappingFile := filepath.Join(t.TempDir(), "emails.csv")
+	if err := os.WriteFile(mappingFile, []byte("# username,email\nTester2, tester2@example.com\n"), 0o600); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	emails, err := client.LoadEmailMapping(mappingFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	httpClient := &http.Client{Transport: mockTransport}
+	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
+	testClient := client.NewClient("api-key", "api-token", test.OrganizationIDs, baseHttpClient)
+	testClient.EnterpriseID = "enterpriseTest"
+	testClient.EmailsByUsername = emails
+
+	users, _, err := testClient.ListUsers(context.Background(), test.OrganizationIDs[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedEmails := []string{"tester1@example.com", "tester2@example.com"}
+	for index, user := range users {
+		if user.Email != expectedEmails[index] {
+			t.Errorf("Expected email %s for %s, got %s", expectedEmails[index], user.Username, user.Email)
+		}
+	}
+
+	userResource, err := parseIntoUserResource(context.Background(), &users[0], nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	userTrait, err := resource.GetUserTrait(userResource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(userTrait.GetEmails()) != 1 || userTrait.GetEmails()[0].GetAddress() != expectedEmails[0] || !userTrait.GetEmails()[0].GetIsPrimary() {
+		t.Errorf("Expected primary email %s, got %v", expectedEmails[0], userTrait.GetEmails())
+	}
+}
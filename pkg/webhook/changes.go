@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-trello/pkg/client"
+)
+
+// Changes lists the boards, organizations and members that changed since they were last synced.
+type Changes struct {
+	Boards        []string  `json:"boards"`
+	Organizations []string  `json:"organizations"`
+	Members       []string  `json:"members"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Empty reports whether no changes were recorded.
+func (c *Changes) Empty() bool {
+	return len(c.Boards) == 0 && len(c.Organizations) == 0 && len(c.Members) == 0
+}
+
+// ChangeLog records the models touched by webhook actions in a JSON file, so the next sync refreshes only those.
+// The receiver adds to the file while syncs remove what they refreshed, so the file is read again before every
+// change.
+type ChangeLog struct {
+	path          string
+	mutex         sync.Mutex
+	boards        map[string]struct{}
+	organizations map[string]struct{}
+	members       map[string]struct{}
+	updatedAt     time.Time
+}
+
+// OpenChangeLog returns the change log stored at path, keeping the changes already recorded there.
+func OpenChangeLog(path string) (*ChangeLog, error) {
+	changeLog := &ChangeLog{path: path}
+	if err := changeLog.load(); err != nil {
+		return nil, err
+	}
+
+	return changeLog, nil
+}
+
+// load replaces the changes held in memory with the ones stored in the file.
+func (c *ChangeLog) load() error {
+	c.boards = make(map[string]struct{})
+	c.organizations = make(map[string]struct{})
+	c.members = make(map[string]struct{})
+	c.updatedAt = time.Time{}
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("trello-connector: failed to read change log: %w", err)
+	}
+
+	var changes Changes
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return fmt.Errorf("trello-connector: failed to parse change log: %w", err)
+	}
+
+	addAll(c.boards, changes.Boards...)
+	addAll(c.organizations, changes.Organizations...)
+	addAll(c.members, changes.Members...)
+	c.updatedAt = changes.UpdatedAt
+
+	return nil
+}
+
+// Record adds the board, organization and member an action refers to, and saves the change log.
+func (c *ChangeLog) Record(action *client.Action) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	if action.Data.Board != nil {
+		addAll(c.boards, action.Data.Board.ID)
+	}
+	if action.Data.Organization != nil {
+		addAll(c.organizations, action.Data.Organization.ID)
+	}
+	addAll(c.members, action.MemberID())
+	c.updatedAt = action.Date
+
+	return c.save()
+}
+
+// Remove drops the given changes once they have been synced, and saves the change log. Changes recorded since
+// they were read are kept for the next sync.
+func (c *ChangeLog) Remove(changes Changes) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	removeAll(c.boards, changes.Boards...)
+	removeAll(c.organizations, changes.Organizations...)
+	removeAll(c.members, changes.Members...)
+
+	return c.save()
+}
+
+// Changes returns the changes recorded so far, sorted by ID.
+func (c *ChangeLog) Changes() Changes {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.changes()
+}
+
+func (c *ChangeLog) changes() Changes {
+	return Changes{
+		Boards:        sortedKeys(c.boards),
+		Organizations: sortedKeys(c.organizations),
+		Members:       sortedKeys(c.members),
+		UpdatedAt:     c.updatedAt,
+	}
+}
+
+// save writes the change log to a temporary file first, so a crash never leaves a truncated file behind.
+func (c *ChangeLog) save() error {
+	data, err := json.MarshalIndent(c.changes(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("trello-connector: failed to write change log: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("trello-connector: failed to write change log: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("trello-connector: failed to write change log: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("trello-connector: failed to write change log: %w", err)
+	}
+
+	return nil
+}
+
+func addAll(set map[string]struct{}, ids ...string) {
+	for _, id := range ids {
+		if id != "" {
+			set[id] = struct{}{}
+		}
+	}
+}
+
+func removeAll(set map[string]struct{}, ids ...string) {
+	for _, id := range ids {
+		delete(set, id)
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // Trello signs webhook callbacks with HMAC-SHA1.
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// SignatureHeader holds the base64 HMAC-SHA1 of the callback body followed by the callback URL.
+	SignatureHeader = "X-Trello-Webhook"
+
+	maxCallbackSize = 1 << 20
+)
+
+type callback struct {
+	Action client.Action `json:"action"`
+}
+
+// Receiver handles the callbacks Trello sends for registered webhooks. It answers the HEAD request Trello makes when
+// a webhook is created, and records the models of every signed action in the change log.
+type Receiver struct {
+	secret      string
+	callbackURL string
+	changes     *ChangeLog
+}
+
+// NewReceiver returns a receiver verifying callbacks with the application secret of the API key. The callback URL
+// must be the exact URL the webhooks were registered with, since it is part of the signature.
+func NewReceiver(secret, callbackURL string, changes *ChangeLog) *Receiver {
+	return &Receiver{
+		secret:      secret,
+		callbackURL: callbackURL,
+		changes:     changes,
+	}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	l := ctxzap.Extract(req.Context())
+
+	switch req.Method {
+	case http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+		return
+	case http.MethodPost:
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxCallbackSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !r.validSignature(body, req.Header.Get(SignatureHeader)) {
+		l.Warn("trello-connector: rejected webhook callback with an invalid signature")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload callback
+	if err := json.Unmarshal(body, &payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := r.changes.Record(&payload.Action); err != nil {
+		l.Error("trello-connector: failed to record webhook action", zap.String("action_id", payload.Action.ID), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	l.Debug(
+		"trello-connector: recorded webhook action",
+		zap.String("action_id", payload.Action.ID),
+		zap.String("action_type", payload.Action.Type),
+	)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *Receiver) validSignature(body []byte, signature string) bool {
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	return hmac.Equal(Sign(r.secret, r.callbackURL, body), expected)
+}
+
+// Sign returns the HMAC-SHA1 Trello computes over a callback body followed by the callback URL.
+func Sign(secret, callbackURL string, body []byte) []byte {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	mac.Write([]byte(callbackURL))
+
+	return mac.Sum(nil)
+}
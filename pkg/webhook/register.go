@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-trello/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const webhookDescription = "baton-trello change detection"
+
+// Register registers a webhook posting to the callback URL for every synced organization and each of its boards.
+// Models that already have a webhook for the callback URL are skipped, so registering again is safe. It returns
+// the number of webhooks created.
+func Register(ctx context.Context, c *client.TrelloClient, callbackURL string) (int, error) {
+	l := ctxzap.Extract(ctx)
+
+	webhooks, _, err := c.ListWebhooks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("trello-connector: failed to list webhooks: %w", err)
+	}
+
+	registered := make(map[string]bool, len(webhooks))
+	for _, webhook := range webhooks {
+		if webhook.CallbackURL == callbackURL {
+			registered[webhook.IdModel] = true
+		}
+	}
+
+	organizationIDs, err := c.ListOrganizationIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var modelIDs []string
+	for _, organizationID := range organizationIDs {
+		// Note: webhooks need the organization ID, while the configured organizations may be slugs.
+		organization, _, err := c.GetOrganizationDetail(ctx, organizationID)
+		if err != nil {
+			return 0, fmt.Errorf("trello-connector: failed to get organization %s: %w", organizationID, err)
+		}
+		modelIDs = append(modelIDs, organization.ID)
+
+		boards, _, err := c.ListBoards(ctx, organizationID)
+		if err != nil {
+			return 0, fmt.Errorf("trello-connector: failed to list boards of organization %s: %w", organizationID, err)
+		}
+		for _, board := range boards {
+			modelIDs = append(modelIDs, board.ID)
+		}
+	}
+
+	created := 0
+	for _, modelID := range modelIDs {
+		if registered[modelID] {
+			continue
+		}
+
+		_, _, err := c.CreateWebhook(ctx, modelID, callbackURL, webhookDescription)
+		if err != nil {
+			return created, fmt.Errorf("trello-connector: failed to create webhook for %s: %w", modelID, err)
+		}
+		registered[modelID] = true
+		created++
+
+		l.Debug("trello-connector: registered webhook", zap.String("model_id", modelID))
+	}
+
+	return created, nil
+}
@@ -0,0 +1,222 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/conductorone/baton-trello/pkg/client"
+)
+
+const (
+	testSecret    = "api-secret"
+	boardID       = "f7a6a858-ab65-4524-9632-b64a21aa3c79"
+	otherBoardID  = "eef3dd14-929f-4b85-b601-7cc4a484fa97"
+	memberID      = "ea960e6c-f613-4bed-8852-ab012603915b"
+	organization  = "1ed53893-6225-4d74-9806-3eedcbb402dd"
+	organizationS = "organizationTest"
+)
+
+// Tests that the receiver answers Trello's verification request, records signed callbacks and rejects unsigned ones.
+// https://developer.atlassian.com/cloud/trello/guides/rest-api/webhooks/#webhook-signatures
+func TestReceiver(t *testing.T) {
+	changes, err := OpenChangeLog(filepath.Join(t.TempDir(), "changes.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var receiver *Receiver
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		receiver.ServeHTTP(w, req)
+	}))
+	defer server.Close()
+	callbackURL := server.URL + "/"
+	receiver = NewReceiver(testSecret, callbackURL, changes)
+
+	// Trello checks the callback URL with a HEAD request when the webhook is created.
+	resp, err := http.Head(callbackURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d for HEAD, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	body := `{
+		"action": {
+			"id": "64b7f1a0c0ffee0000000002",
+			"type": "makeAdminOfBoard",
+			"date": "2023-07-19T14:01:00.000Z",
+			"data": {"board": {"id": "` + boardID + `", "name": "Finance"}},
+			"member": {"id": "` + memberID + `", "username": "tester1"}
+		},
+		"model": {"id": "` + boardID + `"}
+	}`
+
+	post := func(signature string) int {
+		req, err := http.NewRequest(http.MethodPost, callbackURL, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		req.Header.Set(SignatureHeader, signature)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// An invalid signature is rejected and nothing is recorded.
+	if status := post(base64.StdEncoding.EncodeToString(Sign("wrong-secret", callbackURL, []byte(body)))); status != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for an invalid signature, got %d", http.StatusUnauthorized, status)
+	}
+	if recorded := changes.Changes(); len(recorded.Boards) != 0 || len(recorded.Members) != 0 {
+		t.Errorf("Expected no changes, got %+v", recorded)
+	}
+
+	// A valid signature over the body and callback URL is recorded.
+	if status := post(base64.StdEncoding.EncodeToString(Sign(testSecret, callbackURL, []byte(body)))); status != http.StatusOK {
+		t.Errorf("Expected status %d for a valid signature, got %d", http.StatusOK, status)
+	}
+
+	// The change log is persisted, so a new change log sees the recorded changes.
+	reopened, err := OpenChangeLog(changes.path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	recorded := reopened.Changes()
+	if !reflect.DeepEqual(recorded.Boards, []string{boardID}) {
+		t.Errorf("Expected boards %v, got %v", []string{boardID}, recorded.Boards)
+	}
+	if !reflect.DeepEqual(recorded.Members, []string{memberID}) {
+		t.Errorf("Expected members %v, got %v", []string{memberID}, recorded.Members)
+	}
+}
+
+// Tests that webhooks are registered for the organization and its boards, skipping models that already have one,
+// based on the documented APIs below.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-tokens/#api-tokens-token-webhooks-get
+// https://developer.atlassian.com/cloud/trello/rest/api-group-webhooks/#api-webhooks-post
+func TestRegister(t *testing.T) {
+	const callbackURL = "https://baton.example.com/trello"
+
+	var (
+		mutex   sync.Mutex
+		created []map[string]string
+	)
+	trello := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/1/tokens/api-token/webhooks":
+			_, _ = io.WriteString(w, `[{"id": "webhook-1", "idModel": "`+boardID+`", "callbackURL": "`+callbackURL+`", "active": true}]`)
+		case req.Method == http.MethodGet && req.URL.Path == "/1/organizations/"+organizationS:
+			_, _ = io.WriteString(w, `{"id": "`+organization+`", "name": "`+organizationS+`"}`)
+		case req.Method == http.MethodGet && req.URL.Path == "/1/organizations/"+organizationS+"/boards":
+			_, _ = io.WriteString(w, `[{"id": "`+boardID+`"}, {"id": "`+otherBoardID+`"}]`)
+		case req.Method == http.MethodPost && req.URL.Path == "/1/webhooks":
+			var body map[string]string
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mutex.Lock()
+			created = append(created, body)
+			mutex.Unlock()
+			_, _ = io.WriteString(w, `{"id": "webhook-2", "idModel": "`+body["idModel"]+`", "callbackURL": "`+body["callbackURL"]+`", "active": true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer trello.Close()
+
+	testClient := client.NewClient("api-key", "api-token", []string{organizationS}, uhttp.NewBaseHttpClient(trello.Client()))
+	testClient.BaseDomain = trello.URL + "/1"
+
+	count, err := Register(context.Background(), testClient, callbackURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 webhooks to be created, got %d", count)
+	}
+
+	var modelIDs []string
+	for _, body := range created {
+		modelIDs = append(modelIDs, body["idModel"])
+		if body["callbackURL"] != callbackURL {
+			t.Errorf("Expected callback URL %s, got %s", callbackURL, body["callbackURL"])
+		}
+	}
+
+	expectedModelIDs := []string{organization, otherBoardID}
+	if !reflect.DeepEqual(modelIDs, expectedModelIDs) {
+		t.Errorf("Expected webhooks for %v, got %v", expectedModelIDs, modelIDs)
+	}
+}
+
+// Tests that a sync removing the changes it read keeps the changes the receiver recorded in the meantime.
+func TestChangeLog_Remove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.json")
+	receiverLog, err := OpenChangeLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	action := func(boardID, memberID string) *client.Action {
+		return &client.Action{
+			Type:   "addMemberToBoard",
+			Data:   client.ActionData{Board: &client.ActionModel{ID: boardID}, IdMember: memberID},
+			Member: &client.User{ID: memberID},
+		}
+	}
+
+	if err := receiverLog.Record(action(boardID, memberID)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The sync reads the changes, and another change arrives before it completes.
+	syncLog, err := OpenChangeLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	synced := syncLog.Changes()
+
+	const otherMemberID = "8b21d0aa-39a4-4c09-86d2-d29dff8d261f"
+	if err := receiverLog.Record(action(otherBoardID, otherMemberID)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := syncLog.Remove(synced); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The receiver doesn't bring back the removed changes when it records the next one.
+	if err := receiverLog.Record(action(otherBoardID, otherMemberID)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reopened, err := OpenChangeLog(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	recorded := reopened.Changes()
+	if !reflect.DeepEqual(recorded.Boards, []string{otherBoardID}) {
+		t.Errorf("Expected boards %v, got %v", []string{otherBoardID}, recorded.Boards)
+	}
+	if !reflect.DeepEqual(recorded.Members, []string{otherMemberID}) {
+		t.Errorf("Expected members %v, got %v", []string{otherMemberID}, recorded.Members)
+	}
+}
@@ -1,9 +1,15 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/conductorone/baton-trello/pkg/client"
@@ -18,6 +24,7 @@ var (
 // Custom RoundTripper for testing.
 type TestRoundTripper struct {
 	response *http.Response
+	body     []byte
 	err      error
 }
 
@@ -35,13 +42,27 @@ func (m *MockRoundTripper) SetRoundTrip(roundTrip func(*http.Request) (*http.Res
 	m.roundTrip = roundTrip
 }
 
+// RoundTrip replays the same response for every request, so clients that make several calls can be tested.
 func (t *TestRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
-	return t.response, t.err
+	if t.response == nil {
+		return nil, t.err
+	}
+
+	response := *t.response
+	response.Body = io.NopCloser(bytes.NewReader(t.body))
+	return &response, t.err
 }
 
 // Helper function to create a test client with custom transport.
 func NewTestClient(response *http.Response, err error) *client.TrelloClient {
 	transport := &TestRoundTripper{response: response, err: err}
+	if response != nil && response.Body != nil {
+		body, readErr := io.ReadAll(response.Body)
+		if readErr != nil {
+			log.Fatal(readErr)
+		}
+		transport.body = body
+	}
 	httpClient := &http.Client{Transport: transport}
 	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
 	return client.NewClient("", "", OrganizationIDs, baseHttpClient)
@@ -55,3 +76,123 @@ func ReadFile(fileName string) string {
 
 	return string(data)
 }
+
+// Membership is a membership held by a MembershipServer.
+type Membership struct {
+	ID          string `json:"id"`
+	MemberID    string `json:"idMember"`
+	MemberType  string `json:"memberType"`
+	Deactivated bool   `json:"deactivated"`
+}
+
+// MembershipServer is a stand-in for the Trello memberships endpoints of organizations and boards that keeps the
+// changes made through it, so a grant can be followed by a revoke.
+type MembershipServer struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	memberships map[string]map[string]*Membership
+	requests    []string
+}
+
+// NewMembershipServer starts a server holding the given memberships, keyed by model path, e.g.
+// "/organizations/organizationTest", and then by member ID.
+func NewMembershipServer(memberships map[string]map[string]*Membership) *MembershipServer {
+	if memberships == nil {
+		memberships = make(map[string]map[string]*Membership)
+	}
+	server := &MembershipServer{memberships: memberships}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serve))
+
+	return server
+}
+
+// Client returns a Trello client pointed at the server.
+func (s *MembershipServer) Client() *client.TrelloClient {
+	testClient := client.NewClient("api-key", "api-token", OrganizationIDs, uhttp.NewBaseHttpClient(s.Server.Client()))
+	testClient.BaseDomain = s.URL + "/1"
+
+	return testClient
+}
+
+// Membership returns the membership a member holds in a model, or nil when the member doesn't belong to it.
+func (s *MembershipServer) Membership(model, memberID string) *Membership {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.memberships[model][memberID]
+}
+
+// Requests returns the method and path of the requests made to the server that changed a membership.
+func (s *MembershipServer) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *MembershipServer) serve(w http.ResponseWriter, req *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	path := strings.TrimPrefix(req.URL.Path, "/1")
+	if model, ok := strings.CutSuffix(path, "/memberships"); ok && req.Method == http.MethodGet {
+		memberships := []*Membership{}
+		for _, membership := range s.memberships[model] {
+			memberships = append(memberships, membership)
+		}
+		_ = json.NewEncoder(w).Encode(memberships)
+		return
+	}
+
+	s.requests = append(s.requests, req.Method+" "+path)
+
+	deactivated := strings.HasSuffix(path, "/deactivated")
+	model, memberID, ok := strings.Cut(strings.TrimSuffix(path, "/deactivated"), "/members/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	membership := s.memberships[model][memberID]
+	switch {
+	case req.Method == http.MethodPut && deactivated:
+		var body struct {
+			Value bool `json:"value"`
+		}
+		if membership == nil || json.NewDecoder(req.Body).Decode(&body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		membership.Deactivated = body.Value
+	case req.Method == http.MethodPut:
+		var body struct {
+			Type string `json:"type"`
+		}
+		if json.NewDecoder(req.Body).Decode(&body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if s.memberships[model] == nil {
+			s.memberships[model] = make(map[string]*Membership)
+		}
+		if membership == nil {
+			membership = &Membership{ID: "membership-" + memberID, MemberID: memberID}
+			s.memberships[model][memberID] = membership
+		}
+		membership.MemberType = body.Type
+	case req.Method == http.MethodDelete:
+		if membership == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(s.memberships[model], memberID)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	_, _ = io.WriteString(w, `{}`)
+}